@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"arcurachat_api/models"
+	"arcurachat_api/utils"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -28,11 +29,57 @@ func ConnectDatabase() {
 	}
 
 	fmt.Println("✅ Veritabanına bağlantı başarılı")
+	Migrate(db)
+	DB = db
+}
+
+// 🔥 Tabloları oluştur/güncelle ve bir kerelik veri göçlerini çalıştır
+func Migrate(db *gorm.DB) {
+	db.AutoMigrate(&models.SchemaMigration{})
 	db.AutoMigrate(&models.User{})
 	db.AutoMigrate(&models.Message{})
 	db.AutoMigrate(&models.Group{})
 	db.AutoMigrate(&models.GroupMember{})
+
+	runOnce(db, "friendships_dedupe", dedupeFriendships)
 	db.AutoMigrate(&models.Friendship{})
 	db.AutoMigrate(&models.FriendRequest{})
-	DB = db
+	db.AutoMigrate(&models.Conversation{})
+	db.AutoMigrate(&models.ConversationParticipant{})
+	runOnce(db, "conversations_direct_key", backfillDirectKeys)
+	runOnce(db, "friendships_direct_conversations", backfillFriendConversations)
+	db.AutoMigrate(&models.GroupBan{})
+	db.AutoMigrate(&models.GroupMute{})
+	db.AutoMigrate(&models.ModerationLog{})
+	db.AutoMigrate(&models.UserBlock{})
+	db.AutoMigrate(&models.PrivacySettings{})
+	db.AutoMigrate(&models.ThreadReadState{})
+	db.AutoMigrate(&models.MessageReaction{})
+	db.AutoMigrate(&models.MessageRevision{})
+	db.AutoMigrate(&models.MessageHide{})
+	db.AutoMigrate(&models.Attachment{})
+	db.AutoMigrate(&models.AttachmentThumbnail{})
+	db.AutoMigrate(&models.LinkPreview{})
+	db.AutoMigrate(&models.MessageMention{})
+	db.AutoMigrate(&models.PinnedMessage{})
+	db.AutoMigrate(&models.ScheduledMessage{})
+	runOnce(db, "users_phone_hash", backfillPhoneHashes)
+}
+
+// ✅ Telefon özeti olmayan eski kullanıcılar için özeti hesapla
+// Yeni kullanıcıların özeti kayıt ve güncelleme sırasında hesaplandığından bir kez çalışması yeterlidir.
+func backfillPhoneHashes(db *gorm.DB) error {
+	var users []models.User
+	return db.Where("phone_hash = '' OR phone_hash IS NULL").FindInBatches(&users, 500, func(tx *gorm.DB, batch int) error {
+		for _, user := range users {
+			e164, err := utils.NormalizePhoneNumber(user.PhoneNumber)
+			if err != nil {
+				continue
+			}
+			if err := tx.Model(&user).Update("phone_hash", utils.HashPhoneNumber(e164)).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
 }
@@ -0,0 +1,93 @@
+package database
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"arcurachat_api/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 🔥 Veri göçünü sadece bir kez çalıştır
+// Göç ve kaydı aynı transaction içindedir; hata olursa bir sonraki açılışta tekrar denenir.
+// Birden fazla sunucu aynı anda açılırsa kayıt satırı kilitlenerek sadece biri çalıştırır.
+func runOnce(db *gorm.DB, name string, migrate func(tx *gorm.DB) error) {
+	err := db.Transaction(func(tx *gorm.DB) error {
+		migration := models.SchemaMigration{Name: name, AppliedAt: time.Now()}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&migration)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		return migrate(tx)
+	})
+	if err != nil {
+		log.Printf("Uyarı: %s göçü çalıştırılamadı - %v", name, err)
+	}
+}
+
+// 🔥 Benzersiz (user_id, friend_id) index'i öncesi silinmiş ve yinelenen kayıtları temizle
+func dedupeFriendships(tx *gorm.DB) error {
+	if !tx.Migrator().HasTable(&models.Friendship{}) {
+		return nil
+	}
+	if err := tx.Exec("DELETE FROM friendships WHERE deleted_at IS NOT NULL").Error; err != nil {
+		return err
+	}
+	return tx.Exec("DELETE FROM friendships a USING friendships b WHERE a.user_id = b.user_id AND a.friend_id = b.friend_id AND a.id > b.id").Error
+}
+
+// ✅ Mevcut birebir konuşmalara katılımcı çiftinden anahtar ata
+// Aynı çift için birden fazla konuşma varsa en eskisi anahtarı alır.
+func backfillDirectKeys(tx *gorm.DB) error {
+	return tx.Exec(`UPDATE conversations c SET direct_key = p.direct_key FROM (
+		SELECT DISTINCT ON (direct_key) conversation_id, direct_key FROM (
+			SELECT cp.conversation_id, MIN(cp.user_id)::text || ':' || MAX(cp.user_id)::text AS direct_key
+			FROM conversation_participants cp
+			JOIN conversations d ON d.id = cp.conversation_id AND d.type = ? AND d.direct_key IS NULL
+			WHERE cp.deleted_at IS NULL
+			GROUP BY cp.conversation_id
+			HAVING COUNT(DISTINCT cp.user_id) = 2
+		) pairs
+		WHERE NOT EXISTS (SELECT 1 FROM conversations e WHERE e.direct_key = pairs.direct_key)
+		ORDER BY direct_key, conversation_id
+	) p WHERE c.id = p.conversation_id`, models.ConversationTypeDirect).Error
+}
+
+// ✅ Birebir konuşması olmayan eski arkadaşlıklar için konuşmayı oluştur
+// Yeni arkadaşlıkların konuşması istek kabul edilirken oluşturulur.
+func backfillFriendConversations(tx *gorm.DB) error {
+	var pairs []struct {
+		UserID   uint
+		FriendID uint
+	}
+	if err := tx.Raw(`SELECT f.user_id, f.friend_id FROM friendships f
+		WHERE f.user_id < f.friend_id AND f.deleted_at IS NULL
+		AND NOT EXISTS (
+			SELECT 1 FROM conversations c
+			WHERE c.direct_key = f.user_id::text || ':' || f.friend_id::text AND c.deleted_at IS NULL
+		)`).Scan(&pairs).Error; err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, pair := range pairs {
+		key := fmt.Sprintf("%d:%d", pair.UserID, pair.FriendID)
+		conversation := models.Conversation{Type: models.ConversationTypeDirect, DirectKey: &key}
+		if err := tx.Create(&conversation).Error; err != nil {
+			return err
+		}
+		participants := []models.ConversationParticipant{
+			{ConversationID: conversation.ID, UserID: pair.UserID, JoinedAt: now},
+			{ConversationID: conversation.ID, UserID: pair.FriendID, JoinedAt: now},
+		}
+		if err := tx.Create(&participants).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
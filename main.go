@@ -1,15 +1,43 @@
 package main
 
 import (
+	"log"
+
 	"arcurachat_api/database"
+	"arcurachat_api/realtime"
 	"arcurachat_api/routes"
+	"arcurachat_api/storage"
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
+	// Dosya depolamayı hazırla
+	storage.Init()
+
+	// Defter açıksa kayıt fonksiyonunun atanmış olduğunu doğrula
+	if err := routes.CheckLedgerConfig(); err != nil {
+		log.Fatal(err)
+	}
+
 	// Veritabanına bağlan
 	database.ConnectDatabase()
 
+	// Çevrimiçi durum geçişlerini yayınla ve son görülme zamanlarını kaydet
+	go realtime.Presence.Run()
+	go realtime.Signals.Run()
+
+	// Yüklenen görsel ve videoları arka planda işle
+	routes.StartMediaWorkers()
+
+	// Mesajlardaki bağlantıların önizlemelerini arka planda hazırla
+	routes.StartLinkPreviewWorkers()
+
+	// Mesaja bağlanmadan bekleyen eski ekleri temizle
+	go routes.RunAttachmentCleanup()
+
+	// Zamanı gelen zamanlanmış mesajları gönder
+	go routes.RunMessageScheduler()
+
 	// Gin Router başlat
 	r := gin.Default()
 
@@ -19,6 +47,12 @@ func main() {
 	routes.RegisterGroupRoutes(r)
 	routes.RegisterSearchRoutes(r)
 	routes.RegisterFriendRoutes(r)
+	routes.RegisterConversationRoutes(r)
+	routes.RegisterContactRoutes(r)
+	routes.RegisterPresenceRoutes(r)
+	routes.RegisterRealtimeRoutes(r)
+	routes.RegisterAttachmentRoutes(r)
+	routes.RegisterMentionRoutes(r)
 
 	// Sunucuyu başlat
 	r.Run(":8080")
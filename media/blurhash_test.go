@@ -0,0 +1,74 @@
+package media
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func solidImage(width, height int, c color.Color) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestEncodeBase83(t *testing.T) {
+	tests := []struct {
+		value, length int
+		want          string
+	}{
+		{0, 1, "0"},
+		{21, 1, "L"},
+		{82, 1, "~"},
+		{83, 2, "10"},
+		{3429, 2, "fQ"},
+		{16711680, 4, "TI:j"},
+		{16777215, 4, "TSUA"},
+	}
+
+	for _, tt := range tests {
+		if got := encodeBase83(tt.value, tt.length); got != tt.want {
+			t.Errorf("encodeBase83(%d, %d) = %q, want %q", tt.value, tt.length, got, tt.want)
+		}
+	}
+}
+
+func TestBlurHash(t *testing.T) {
+	tests := []struct {
+		name       string
+		img        image.Image
+		x, y       int
+		want       string // Beklenen tam değer, boşsa kontrol edilmez
+		dc         string // Ortalama rengi taşıyan 4 karakter
+		wantLength int
+	}{
+		{"black 4x3", solidImage(8, 6, color.Black), 4, 3, "L00000" + strings.Repeat("fQ", 11), "0000", 28},
+		{"white 3x4", solidImage(6, 8, color.White), 3, 4, "", "TSUA", 28},
+		{"red 1x1", solidImage(4, 4, color.NRGBA{R: 255, A: 255}), 1, 1, "00TI:j", "TI:j", 6},
+		{"gradient 4x3", testImage(16, 12), 4, 3, "", "", 28},
+		{"empty", image.NewNRGBA(image.Rect(0, 0, 0, 0)), 4, 3, "", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BlurHash(tt.img, tt.x, tt.y)
+			if len(got) != tt.wantLength {
+				t.Fatalf("BlurHash() = %q, length %d, want %d", got, len(got), tt.wantLength)
+			}
+			if tt.want != "" && got != tt.want {
+				t.Fatalf("BlurHash() = %q, want %q", got, tt.want)
+			}
+			if tt.dc != "" && got[2:6] != tt.dc {
+				t.Fatalf("BlurHash() DC = %q, want %q", got[2:6], tt.dc)
+			}
+			if got != "" && got[0] != base83Chars[(tt.x-1)+(tt.y-1)*9] {
+				t.Fatalf("size flag = %q, want components %dx%d", got[0], tt.x, tt.y)
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	_ "image/gif" // image.Decode için GIF desteği (ilk kare)
+	"image/jpeg"
+	"image/png"
+	"strconv"
+
+	"arcurachat_api/utils"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // image.Decode için WebP desteği
+)
+
+// Çözülecek görselin en fazla piksel sayısı; küçük dosyada çok büyük boyut bildiren
+// görseller (decompression bomb) belleği tüketmeden reddedilir.
+var maxImagePixels = utils.GetEnvInt("MEDIA_MAX_PIXELS", 40000000)
+
+// ✅ Görsel boyutu sınırı aşıldığında dönen hata
+var ErrImageTooLarge = errors.New("görsel boyutu çok büyük")
+
+// ✅ Görseli önce başlığındaki boyutu kontrol ederek çöz
+func decodeImage(data []byte) (image.Image, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if config.Width <= 0 || config.Height <= 0 || int64(config.Width)*int64(config.Height) > int64(maxImagePixels) {
+		return nil, ErrImageTooLarge
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
+
+// ✅ Üretilen küçük görsel
+type Thumbnail struct {
+	Label       string
+	Width       int
+	Height      int
+	ContentType string
+	Data        []byte
+}
+
+// ✅ Görsel işleme sonucu
+type ImageResult struct {
+	Width      int
+	Height     int
+	Sanitized  []byte // Metaverisi temizlenmiş orijinal (değişmediyse nil)
+	Thumbnails []Thumbnail
+	BlurHash   string
+}
+
+// 🔥 Görseli işle: metaveriyi temizle, boyutları çıkar, küçük görselleri ve BlurHash'i üret
+// sizes, küçük görsellerin en uzun kenar uzunluklarıdır; orijinalden büyük olanlar atlanır.
+func ProcessImage(data []byte, contentType string, sizes []int) (*ImageResult, error) {
+	result := &ImageResult{}
+
+	orientation := 1
+	switch contentType {
+	case "image/jpeg":
+		stripped, o, err := StripJPEG(data)
+		if err != nil {
+			return nil, err
+		}
+		result.Sanitized, orientation = stripped, o
+	case "image/png":
+		stripped, err := StripPNG(data)
+		if err != nil {
+			return nil, err
+		}
+		result.Sanitized = stripped
+	case "image/webp":
+		stripped, err := StripWebP(data)
+		if err != nil {
+			return nil, err
+		}
+		result.Sanitized = stripped
+	}
+
+	source := data
+	if result.Sanitized != nil {
+		source = result.Sanitized
+	}
+
+	img, err := decodeImage(source)
+	if err != nil {
+		return nil, err
+	}
+
+	// EXIF silindiğinde yön bilgisi kaybolmasın diye döndürme piksellere uygulanır
+	if orientation > 1 {
+		img = applyOrientation(img, orientation)
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 92}); err != nil {
+			return nil, err
+		}
+		result.Sanitized = buf.Bytes()
+	}
+
+	thumbnails, blurHash, err := Thumbnails(img, contentType, sizes)
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := img.Bounds()
+	result.Width, result.Height = bounds.Dx(), bounds.Dy()
+	result.Thumbnails = thumbnails
+	result.BlurHash = blurHash
+	return result, nil
+}
+
+// ✅ Görselden küçük görseller ve BlurHash üret
+// Saydamlık içerebilen kaynaklar için PNG, diğerleri için JPEG kullanılır.
+func Thumbnails(img image.Image, contentType string, sizes []int) ([]Thumbnail, string, error) {
+	bounds := img.Bounds()
+	longest := maxInt(bounds.Dx(), bounds.Dy())
+
+	var thumbnails []Thumbnail
+	for _, size := range sizes {
+		if size <= 0 || size >= longest {
+			continue
+		}
+
+		scaled := resize(img, size)
+		var buf bytes.Buffer
+		thumbnail := Thumbnail{
+			Label:  strconv.Itoa(size),
+			Width:  scaled.Bounds().Dx(),
+			Height: scaled.Bounds().Dy(),
+		}
+		if contentType == "image/png" || contentType == "image/gif" || contentType == "image/webp" {
+			thumbnail.ContentType = "image/png"
+			if err := png.Encode(&buf, scaled); err != nil {
+				return nil, "", err
+			}
+		} else {
+			thumbnail.ContentType = "image/jpeg"
+			if err := jpeg.Encode(&buf, scaled, &jpeg.Options{Quality: 80}); err != nil {
+				return nil, "", err
+			}
+		}
+		thumbnail.Data = buf.Bytes()
+		thumbnails = append(thumbnails, thumbnail)
+	}
+
+	// BlurHash küçük bir önizleme üzerinden hesaplanır; bileşen sayısı en-boy oranına göre seçilir
+	xComponents, yComponents := 4, 3
+	if bounds.Dy() > bounds.Dx() {
+		xComponents, yComponents = 3, 4
+	}
+	blurHash := BlurHash(resize(img, 32), xComponents, yComponents)
+
+	return thumbnails, blurHash, nil
+}
+
+// Görseli en uzun kenarı size olacak şekilde ölçekle
+func resize(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width >= height {
+		height = maxInt(1, height*size/width)
+		width = size
+	} else {
+		width = maxInt(1, width*size/height)
+		height = size
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
+
+// EXIF yön değerine (2-8) göre görseli çevir/döndür
+func applyOrientation(img image.Image, orientation int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	// 5-8 arası değerlerde genişlik ve yükseklik yer değiştirir
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 {
+		dstWidth, dstHeight = height, width
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2:
+				dx, dy = width-1-x, y
+			case 3:
+				dx, dy = width-1-x, height-1-y
+			case 4:
+				dx, dy = x, height-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = height-1-y, x
+			case 7:
+				dx, dy = height-1-y, width-1-x
+			case 8:
+				dx, dy = y, width-1-x
+			default:
+				dx, dy = x, y
+			}
+			dst.Set(dx, dy, img.At(bounds.Min.X+x, bounds.Min.Y+y))
+		}
+	}
+	return dst
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,197 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+var errMalformed = errors.New("bozuk görsel dosyası")
+
+// ✅ JPEG'den EXIF/XMP ve yorum bölümlerini yeniden kodlamadan çıkar
+// Renk profili (APP2) ve Adobe (APP14) bölümleri görüntünün doğru çizilmesi için korunur.
+// EXIF yön bilgisi (orientation) de döndürülür; silinen EXIF ile kaybolmaması için çağıran uygulamalıdır.
+func StripJPEG(data []byte) ([]byte, int, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, 0, errMalformed
+	}
+
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(data[:2])
+	orientation := 1
+
+	i := 2
+	for i < len(data) {
+		if data[i] != 0xFF {
+			return nil, 0, errMalformed
+		}
+		// Dolgu baytları
+		for i < len(data) && data[i] == 0xFF {
+			i++
+		}
+		if i >= len(data) {
+			return nil, 0, errMalformed
+		}
+		marker := data[i]
+		i++
+
+		// Uzunluk alanı olmayan işaretler
+		if marker == 0xD8 || marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7) {
+			out.Write([]byte{0xFF, marker})
+			continue
+		}
+		if marker == 0xD9 {
+			out.Write([]byte{0xFF, marker})
+			break
+		}
+
+		if i+2 > len(data) {
+			return nil, 0, errMalformed
+		}
+		length := int(binary.BigEndian.Uint16(data[i : i+2]))
+		if length < 2 || i+length > len(data) {
+			return nil, 0, errMalformed
+		}
+		segment := data[i : i+length]
+
+		// Tarama başladıktan sonrası sıkıştırılmış görüntü verisidir, olduğu gibi kopyalanır
+		if marker == 0xDA {
+			out.Write([]byte{0xFF, marker})
+			out.Write(data[i:])
+			break
+		}
+
+		switch {
+		case marker == 0xE1:
+			if o := exifOrientation(segment[2:]); o != 0 {
+				orientation = o
+			}
+		case marker == 0xE0, marker == 0xE2, marker == 0xEE:
+			out.Write([]byte{0xFF, marker})
+			out.Write(segment)
+		case marker >= 0xE3 && marker <= 0xEF, marker == 0xFE:
+			// Diğer uygulama bölümleri ve yorumlar atılır
+		default:
+			out.Write([]byte{0xFF, marker})
+			out.Write(segment)
+		}
+		i += length
+	}
+
+	return out.Bytes(), orientation, nil
+}
+
+// APP1 içeriğinden EXIF yön etiketini oku (bulunamazsa 0)
+func exifOrientation(app1 []byte) int {
+	if len(app1) < 14 || string(app1[:6]) != "Exif\x00\x00" {
+		return 0
+	}
+	tiff := app1[6:]
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+
+	offset := int(order.Uint32(tiff[4:8]))
+	if offset+2 > len(tiff) {
+		return 0
+	}
+	entries := int(order.Uint16(tiff[offset : offset+2]))
+	for n := 0; n < entries; n++ {
+		entry := offset + 2 + n*12
+		if entry+12 > len(tiff) {
+			return 0
+		}
+		if order.Uint16(tiff[entry:entry+2]) == 0x0112 {
+			value := int(order.Uint16(tiff[entry+8 : entry+10]))
+			if value >= 1 && value <= 8 {
+				return value
+			}
+			return 0
+		}
+	}
+	return 0
+}
+
+// ✅ PNG'den EXIF, metin ve zaman bölümlerini çıkar
+func StripPNG(data []byte) ([]byte, error) {
+	const signature = "\x89PNG\r\n\x1a\n"
+	if len(data) < len(signature) || string(data[:len(signature)]) != signature {
+		return nil, errMalformed
+	}
+
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.WriteString(signature)
+
+	i := len(signature)
+	for i+8 <= len(data) {
+		length := int(binary.BigEndian.Uint32(data[i : i+4]))
+		chunkType := string(data[i+4 : i+8])
+		end := i + 12 + length
+		if length < 0 || end > len(data) {
+			return nil, errMalformed
+		}
+
+		switch chunkType {
+		case "eXIf", "tEXt", "zTXt", "iTXt", "tIME":
+		default:
+			out.Write(data[i:end])
+		}
+
+		i = end
+		if chunkType == "IEND" {
+			break
+		}
+	}
+
+	return out.Bytes(), nil
+}
+
+// ✅ WebP'den EXIF ve XMP bölümlerini çıkar
+func StripWebP(data []byte) ([]byte, error) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, errMalformed
+	}
+
+	body := bytes.NewBuffer(make([]byte, 0, len(data)))
+	body.WriteString("WEBP")
+
+	i := 12
+	for i+8 <= len(data) {
+		fourCC := string(data[i : i+4])
+		size := int(binary.LittleEndian.Uint32(data[i+4 : i+8]))
+		end := i + 8 + size + size%2
+		if size < 0 || i+8+size > len(data) {
+			return nil, errMalformed
+		}
+		if end > len(data) {
+			end = len(data)
+		}
+
+		switch fourCC {
+		case "EXIF", "XMP ":
+		case "VP8X":
+			chunk := append([]byte(nil), data[i:end]...)
+			if len(chunk) > 8 {
+				// EXIF (0x08) ve XMP (0x04) bayraklarını temizle
+				chunk[8] &^= 0x08 | 0x04
+			}
+			body.Write(chunk)
+		default:
+			body.Write(data[i:end])
+		}
+		i = end
+	}
+
+	out := bytes.NewBuffer(make([]byte, 0, body.Len()+8))
+	out.WriteString("RIFF")
+	binary.Write(out, binary.LittleEndian, uint32(body.Len()))
+	out.Write(body.Bytes())
+	return out.Bytes(), nil
+}
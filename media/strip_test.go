@@ -0,0 +1,285 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// EXIF yön etiketi içeren APP1 içeriği üret
+func exifPayload(order binary.ByteOrder, orientation uint16) []byte {
+	var tiff bytes.Buffer
+	if order == binary.LittleEndian {
+		tiff.WriteString("II")
+	} else {
+		tiff.WriteString("MM")
+	}
+	binary.Write(&tiff, order, uint16(42))
+	binary.Write(&tiff, order, uint32(8)) // IFD0 başlangıcı
+	binary.Write(&tiff, order, uint16(1)) // Kayıt sayısı
+	binary.Write(&tiff, order, uint16(0x0112))
+	binary.Write(&tiff, order, uint16(3)) // SHORT
+	binary.Write(&tiff, order, uint32(1))
+	binary.Write(&tiff, order, orientation)
+	binary.Write(&tiff, order, uint16(0))
+	binary.Write(&tiff, order, uint32(0)) // Sonraki IFD yok
+	return append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+}
+
+func jpegSegment(marker byte, payload []byte) []byte {
+	segment := []byte{0xFF, marker, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+func testImage(width, height int) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x * 10), G: uint8(y * 10), B: 128, A: 255})
+		}
+	}
+	return img
+}
+
+// SOI'den hemen sonra verilen bölümleri ekleyerek JPEG üret
+func testJPEG(t *testing.T, segments ...[]byte) []byte {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, testImage(8, 6), nil); err != nil {
+		t.Fatal(err)
+	}
+	encoded := buf.Bytes()
+	out := append([]byte{}, encoded[:2]...)
+	for _, segment := range segments {
+		out = append(out, segment...)
+	}
+	return append(out, encoded[2:]...)
+}
+
+func TestExifOrientation(t *testing.T) {
+	tests := []struct {
+		name string
+		app1 []byte
+		want int
+	}{
+		{"little endian", exifPayload(binary.LittleEndian, 6), 6},
+		{"big endian", exifPayload(binary.BigEndian, 3), 3},
+		{"normal", exifPayload(binary.LittleEndian, 1), 1},
+		{"out of range", exifPayload(binary.LittleEndian, 9), 0},
+		{"not exif", append([]byte("http://ns.adobe.com/xap/1.0/\x00"), make([]byte, 16)...), 0},
+		{"too short", []byte("Exif\x00\x00II"), 0},
+		{"unknown byte order", append([]byte("Exif\x00\x00XX"), make([]byte, 16)...), 0},
+		{"truncated entry", exifPayload(binary.LittleEndian, 6)[:20], 0},
+		{"offset past end", func() []byte {
+			payload := exifPayload(binary.LittleEndian, 6)
+			binary.LittleEndian.PutUint32(payload[10:], 0xFFFFFF)
+			return payload
+		}(), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exifOrientation(tt.app1); got != tt.want {
+				t.Fatalf("exifOrientation() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripJPEG(t *testing.T) {
+	exif := jpegSegment(0xE1, exifPayload(binary.BigEndian, 8))
+	icc := jpegSegment(0xE2, []byte("ICC_PROFILE\x00\x01\x01data"))
+	comment := jpegSegment(0xFE, []byte("secret comment"))
+	app13 := jpegSegment(0xED, []byte("Photoshop 3.0\x00iptc"))
+
+	tests := []struct {
+		name            string
+		data            []byte
+		wantOrientation int
+		removed         [][]byte
+		kept            [][]byte
+		wantErr         bool
+	}{
+		{"plain", testJPEG(t), 1, nil, nil, false},
+		{"exif and comment", testJPEG(t, exif, comment), 8, [][]byte{[]byte("Exif"), []byte("secret comment")}, nil, false},
+		{"keeps icc profile", testJPEG(t, icc, app13), 1, [][]byte{[]byte("Photoshop")}, [][]byte{[]byte("ICC_PROFILE")}, false},
+		{"not a jpeg", []byte("GIF89a......"), 0, nil, nil, true},
+		{"segment length past end", append(testJPEG(t)[:2], 0xFF, 0xE1, 0xFF, 0xFF, 0x00), 0, nil, nil, true},
+		{"segment length too small", append(testJPEG(t)[:2], 0xFF, 0xE1, 0x00, 0x01), 0, nil, nil, true},
+		{"garbage between segments", append(testJPEG(t)[:2], 0x00, 0x01), 0, nil, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, orientation, err := StripJPEG(tt.data)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("StripJPEG() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("StripJPEG() error = %v", err)
+			}
+			if orientation != tt.wantOrientation {
+				t.Fatalf("orientation = %d, want %d", orientation, tt.wantOrientation)
+			}
+			for _, needle := range tt.removed {
+				if bytes.Contains(out, needle) {
+					t.Fatalf("output still contains %q", needle)
+				}
+			}
+			for _, needle := range tt.kept {
+				if !bytes.Contains(out, needle) {
+					t.Fatalf("output lost %q", needle)
+				}
+			}
+			if _, err := jpeg.Decode(bytes.NewReader(out)); err != nil {
+				t.Fatalf("stripped JPEG does not decode: %v", err)
+			}
+		})
+	}
+}
+
+func pngChunk(chunkType string, data []byte) []byte {
+	chunk := make([]byte, 8, 12+len(data))
+	binary.BigEndian.PutUint32(chunk, uint32(len(data)))
+	copy(chunk[4:], chunkType)
+	chunk = append(chunk, data...)
+	return binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+}
+
+// IEND'den önce verilen bölümleri ekleyerek PNG üret
+func testPNG(t *testing.T, chunks ...[]byte) []byte {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, testImage(4, 4)); err != nil {
+		t.Fatal(err)
+	}
+	encoded := buf.Bytes()
+	iend := len(encoded) - 12
+	out := append([]byte{}, encoded[:iend]...)
+	for _, chunk := range chunks {
+		out = append(out, chunk...)
+	}
+	return append(out, encoded[iend:]...)
+}
+
+func TestStripPNG(t *testing.T) {
+	metadata := [][]byte{
+		pngChunk("tEXt", []byte("Author\x00someone")),
+		pngChunk("iTXt", []byte("XML:com.adobe.xmp\x00\x00\x00\x00\x00<x:xmpmeta/>")),
+		pngChunk("zTXt", []byte("Comment\x00\x00x")),
+		pngChunk("tIME", []byte{0x07, 0xE6, 1, 2, 3, 4, 5}),
+		pngChunk("eXIf", exifPayload(binary.BigEndian, 6)[6:]),
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		removed []string
+		wantErr bool
+	}{
+		{"plain", testPNG(t), nil, false},
+		{"metadata chunks", testPNG(t, metadata...), []string{"tEXt", "iTXt", "zTXt", "tIME", "eXIf", "someone", "xmpmeta"}, false},
+		{"bad signature", []byte("\x89PNX\r\n\x1a\n"), nil, true},
+		{"chunk past end", append(testPNG(t)[:33], 0x00, 0x00, 0xFF, 0xFF, 't', 'E', 'X', 't'), nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := StripPNG(tt.data)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("StripPNG() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("StripPNG() error = %v", err)
+			}
+			for _, needle := range tt.removed {
+				if bytes.Contains(out, []byte(needle)) {
+					t.Fatalf("output still contains %q", needle)
+				}
+			}
+			if !bytes.Equal(out, testPNG(t)) {
+				t.Fatal("stripped PNG differs from the original image data")
+			}
+			if _, err := png.Decode(bytes.NewReader(out)); err != nil {
+				t.Fatalf("stripped PNG does not decode: %v", err)
+			}
+		})
+	}
+}
+
+func webpChunk(fourCC string, data []byte) []byte {
+	chunk := make([]byte, 8, 9+len(data))
+	copy(chunk, fourCC)
+	binary.LittleEndian.PutUint32(chunk[4:], uint32(len(data)))
+	chunk = append(chunk, data...)
+	if len(data)%2 == 1 {
+		chunk = append(chunk, 0)
+	}
+	return chunk
+}
+
+func testWebP(chunks ...[]byte) []byte {
+	body := []byte("WEBP")
+	for _, chunk := range chunks {
+		body = append(body, chunk...)
+	}
+	out := []byte("RIFF")
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(body)))
+	return append(out, body...)
+}
+
+func TestStripWebP(t *testing.T) {
+	vp8x := webpChunk("VP8X", []byte{0x08 | 0x04 | 0x10, 0, 0, 0, 7, 0, 0, 5, 0, 0})
+	frame := webpChunk("VP8 ", []byte("frame-data"))
+	odd := webpChunk("ALPH", []byte("abc")) // Tek uzunluklu bölüm dolgu baytıyla biter
+	exif := webpChunk("EXIF", exifPayload(binary.LittleEndian, 6)[6:])
+	xmp := webpChunk("XMP ", []byte("<x:xmpmeta/>"))
+
+	tests := []struct {
+		name      string
+		data      []byte
+		want      []byte
+		wantErr   bool
+		wantFlags byte
+	}{
+		{"plain", testWebP(frame), testWebP(frame), false, 0},
+		{"exif and xmp", testWebP(vp8x, odd, frame, exif, xmp),
+			testWebP(webpChunk("VP8X", []byte{0x10, 0, 0, 0, 7, 0, 0, 5, 0, 0}), odd, frame), false, 0x10},
+		{"not webp", []byte("RIFF\x04\x00\x00\x00WAVE"), nil, true, 0},
+		{"chunk past end", testWebP([]byte("EXIF\xff\x00\x00\x00abc")), nil, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := StripWebP(tt.data)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("StripWebP() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("StripWebP() error = %v", err)
+			}
+			if !bytes.Equal(out, tt.want) {
+				t.Fatalf("StripWebP() = %q, want %q", out, tt.want)
+			}
+			if size := binary.LittleEndian.Uint32(out[4:8]); int(size) != len(out)-8 {
+				t.Fatalf("RIFF size = %d, want %d", size, len(out)-8)
+			}
+			if tt.wantFlags != 0 && out[20] != tt.wantFlags {
+				t.Fatalf("VP8X flags = %#x, want %#x", out[20], tt.wantFlags)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package media
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// ✅ ffmpeg/ffprobe kurulu değilse dönen hata
+var ErrToolsUnavailable = errors.New("ffmpeg veya ffprobe bulunamadı")
+
+// Tek bir videonun işlenmesi için üst süre sınırı
+const videoTimeout = 2 * time.Minute
+
+// ✅ Video işleme sonucu
+type VideoResult struct {
+	Width      int
+	Height     int
+	DurationMs int64
+	Sanitized  []byte // Konum dahil metaverisi temizlenmiş video
+	Thumbnails []Thumbnail
+	BlurHash   string
+}
+
+// ✅ Video işleme araçları kurulu mu?
+func VideoToolsAvailable() bool {
+	_, ffmpegErr := exec.LookPath("ffmpeg")
+	_, ffprobeErr := exec.LookPath("ffprobe")
+	return ffmpegErr == nil && ffprobeErr == nil
+}
+
+// 🔥 Videoyu işle: boyut ve süreyi oku, metaveriyi temizle, kapak karesinden küçük görseller üret
+// Videolar ffmpeg ile yeniden kodlanmadan (stream copy) temizlenir.
+func ProcessVideo(data []byte, contentType string, sizes []int) (*VideoResult, error) {
+	if !VideoToolsAvailable() {
+		return nil, ErrToolsUnavailable
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), videoTimeout)
+	defer cancel()
+
+	dir, err := os.MkdirTemp("", "arcura-video-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	ext := ".mp4"
+	if contentType == "video/webm" {
+		ext = ".webm"
+	}
+	input := filepath.Join(dir, "input"+ext)
+	output := filepath.Join(dir, "output"+ext)
+	if err := os.WriteFile(input, data, 0o600); err != nil {
+		return nil, err
+	}
+
+	result := &VideoResult{}
+
+	// Boyut ve süre
+	probe, err := exec.CommandContext(ctx, "ffprobe", "-v", "error",
+		"-select_streams", "v:0",
+		"-show_entries", "stream=width,height:format=duration",
+		"-of", "json", input).Output()
+	if err != nil {
+		return nil, err
+	}
+	var info struct {
+		Streams []struct {
+			Width  int `json:"width"`
+			Height int `json:"height"`
+		} `json:"streams"`
+		Format struct {
+			Duration string `json:"duration"`
+		} `json:"format"`
+	}
+	if err := json.Unmarshal(probe, &info); err != nil {
+		return nil, err
+	}
+	if len(info.Streams) > 0 {
+		result.Width, result.Height = info.Streams[0].Width, info.Streams[0].Height
+	}
+	if seconds, err := strconv.ParseFloat(info.Format.Duration, 64); err == nil {
+		result.DurationMs = int64(seconds * 1000)
+	}
+
+	// Konum (©xyz) dahil tüm metaveriyi at
+	if err := exec.CommandContext(ctx, "ffmpeg", "-v", "error", "-y", "-i", input,
+		"-map", "0", "-map_metadata", "-1", "-c", "copy", output).Run(); err != nil {
+		return nil, err
+	}
+	if result.Sanitized, err = os.ReadFile(output); err != nil {
+		return nil, err
+	}
+
+	// Kapak karesi
+	frame, err := exec.CommandContext(ctx, "ffmpeg", "-v", "error", "-i", input,
+		"-frames:v", "1", "-f", "image2pipe", "-vcodec", "png", "-").Output()
+	if err != nil || len(frame) == 0 {
+		return result, nil
+	}
+	poster, err := decodeImage(frame)
+	if err != nil {
+		return result, nil
+	}
+	if result.Thumbnails, result.BlurHash, err = Thumbnails(poster, "image/jpeg", sizes); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,54 @@
+package models
+
+import "gorm.io/gorm"
+
+// ✅ Ek türleri
+const (
+	AttachmentKindImage = "image"
+	AttachmentKindVideo = "video"
+	AttachmentKindAudio = "audio"
+	AttachmentKindFile  = "file"
+)
+
+// ✅ Ek işleme durumları
+// Görsel ve videolar metaverisi temizlenene kadar indirilemez.
+const (
+	AttachmentStatusProcessing = "processing"
+	AttachmentStatusReady      = "ready"
+	AttachmentStatusFailed     = "failed"
+)
+
+// 🔥 Mesaj eki
+// Önce yüklenir (MessageID boş), ardından mesaj gönderilirken mesaja bağlanır.
+type Attachment struct {
+	gorm.Model
+	UploaderID     uint   `gorm:"index" json:"uploader_id"`
+	ConversationID uint   `gorm:"index" json:"conversation_id"`
+	MessageID      *uint  `gorm:"index" json:"message_id"`
+	StorageKey     string `gorm:"not null" json:"-"`
+	FileName       string `json:"file_name"`
+	ContentType    string `json:"content_type"`
+	Kind           string `json:"kind"`
+	Size           int64  `json:"size"`
+
+	// Arka planda çıkarılan medya bilgileri
+	Status     string                `gorm:"default:ready" json:"status"`
+	Width      int                   `json:"width,omitempty"`
+	Height     int                   `json:"height,omitempty"`
+	DurationMs int64                 `json:"duration_ms,omitempty"`
+	BlurHash   string                `json:"blurhash,omitempty"`
+	Thumbnails []AttachmentThumbnail `gorm:"-" json:"thumbnails,omitempty"`
+}
+
+// ✅ Ekin küçük görseli
+type AttachmentThumbnail struct {
+	gorm.Model
+	AttachmentID uint   `gorm:"index" json:"attachment_id"`
+	Label        string `json:"label"` // En uzun kenar uzunluğu, örn. "320"
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	ContentType  string `json:"content_type"`
+	Size         int64  `json:"size"`
+	StorageKey   string `gorm:"not null" json:"-"`
+	URL          string `gorm:"-" json:"url,omitempty"` // Süreli imzalı bağlantı
+}
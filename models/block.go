@@ -0,0 +1,12 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// ✅ Kullanıcı Engelleme Modeli
+type UserBlock struct {
+	gorm.Model
+	BlockerID uint `gorm:"uniqueIndex:idx_user_blocks_pair" json:"blocker_id"` // Engelleyen
+	BlockedID uint `gorm:"uniqueIndex:idx_user_blocks_pair" json:"blocked_id"` // Engellenen
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ✅ Konuşma tipleri
+const (
+	ConversationTypeDirect = "direct"
+	ConversationTypeGroup  = "group"
+)
+
+// 🔥 Konuşma Modeli
+type Conversation struct {
+	gorm.Model
+	Type      string  `json:"type"`                         // direct, group
+	GroupID   *uint   `json:"group_id"`                     // Grup konuşmasıysa bağlı grup
+	DirectKey *string `gorm:"uniqueIndex;size:50" json:"-"` // Birebir konuşmada "küçükID:büyükID", aynı çift için tek konuşma
+}
+
+// ✅ Konuşma katılımcısı modeli
+// Her üyelik dönemi ayrı bir kayıttır; çıkarılan üyenin LeftAt alanı dolar
+// ve bu tarihe kadar olan geçmişi görmeye devam eder.
+type ConversationParticipant struct {
+	gorm.Model
+	ConversationID    uint       `gorm:"index" json:"conversation_id"`
+	UserID            uint       `gorm:"index" json:"user_id"`
+	JoinedAt          time.Time  `json:"joined_at"`
+	LeftAt            *time.Time `json:"left_at"`                               // Konuşmadan ayrıldığı zaman
+	LastReadMessageID uint       `gorm:"default:0" json:"last_read_message_id"` // Okunan son mesaj
+	Muted             bool       `gorm:"default:false" json:"muted"`            // Bildirimler sessizde (bahsetmeler yine bildirilir)
+	MutedUntil        *time.Time `json:"muted_until"`                           // Sessize alma bitişi, boşsa süresiz
+}
+
+// ✅ Katılımcı konuşmanın bildirimlerini şu an sessize almış mı?
+func (p ConversationParticipant) IsMuted(now time.Time) bool {
+	return p.Muted && (p.MutedUntil == nil || p.MutedUntil.After(now))
+}
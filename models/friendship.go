@@ -4,17 +4,27 @@ import (
 	"gorm.io/gorm"
 )
 
+// ✅ Arkadaşlık isteği durumları
+const (
+	FriendRequestPending   = "pending"
+	FriendRequestAccepted  = "accepted"
+	FriendRequestRejected  = "rejected"
+	FriendRequestCancelled = "cancelled"
+	FriendRequestExpired   = "expired"
+)
+
 // ✅ Arkadaşlık İstek Modeli
 type FriendRequest struct {
 	gorm.Model
-	SenderID   uint `json:"sender_id"`
-	ReceiverID uint `json:"receiver_id"`
-	Status     string `json:"status"` // pending, accepted, rejected
+	SenderID   uint `gorm:"index" json:"sender_id"`
+	ReceiverID uint `gorm:"index" json:"receiver_id"`
+	Status     string `json:"status"` // pending, accepted, rejected, cancelled, expired
 }
 
 // ✅ Arkadaşlık Modeli
+// Her arkadaşlık iki yönlü kayıttan oluşur, (user_id, friend_id) çifti benzersizdir.
 type Friendship struct {
 	gorm.Model
-	UserID   uint `json:"user_id"`
-	FriendID uint `json:"friend_id"`
+	UserID   uint `gorm:"uniqueIndex:idx_friendships_pair" json:"user_id"`
+	FriendID uint `gorm:"uniqueIndex:idx_friendships_pair" json:"friend_id"`
 }
@@ -4,17 +4,47 @@ import (
 	"gorm.io/gorm"
 )
 
+// ✅ Grup üyelik rolleri
+const (
+	GroupRoleOwner  = "owner"
+	GroupRoleAdmin  = "admin"
+	GroupRoleMember = "member"
+)
+
+// ✅ Grup tipleri
+const (
+	GroupTypeGroup   = "group"
+	GroupTypeChannel = "channel" // Sadece yöneticilerin paylaşım yaptığı yayın kanalı
+)
+
+// ✅ Grup ayarları
+type GroupSettings struct {
+	OnlyAdminsCanPost     bool `gorm:"not null;default:false" json:"only_admins_can_post"`     // Sadece yöneticiler mesaj gönderebilir
+	OnlyAdminsCanEditInfo bool `gorm:"not null;default:true" json:"only_admins_can_edit_info"` // Grup bilgilerini sadece yöneticiler düzenler
+	MembersCanInvite      bool `gorm:"not null;default:false" json:"members_can_invite"`       // Üyeler de gruba kullanıcı ekleyebilir
+	SlowModeSeconds       int  `gorm:"not null;default:0" json:"slow_mode_seconds"`            // Üyenin iki mesajı arasındaki en kısa süre
+	MembersCanPin         bool `gorm:"not null;default:false" json:"members_can_pin"`          // Üyeler de mesaj sabitleyebilir
+}
+
 // ✅ Grup modeli
 type Group struct {
 	gorm.Model
-	Name    string         `json:"name"`
-	OwnerID uint           `json:"owner_id"`  // 🔥 Grup sahibi eklendi
-	Members []GroupMember `json:"members"`
+	Name           string        `json:"name"`
+	Type           string        `gorm:"default:group;index" json:"type"` // group, channel
+	Description    string        `json:"description"`
+	AvatarURL      string        `json:"avatar_url"`
+	Topic          string        `json:"topic"`
+	OwnerID        uint          `json:"owner_id"`        // 🔥 Grup sahibi eklendi
+	ConversationID uint          `json:"conversation_id"` // 🔥 Grubun mesajlaşma konuşması
+	Settings       GroupSettings `gorm:"embedded;embeddedPrefix:setting_" json:"settings"`
+	MemberCount    int64         `gorm:"-" json:"member_count"`
+	Members        []GroupMember `json:"members,omitempty"`
 }
 
 // ✅ Grup üyeleri için model
 type GroupMember struct {
 	gorm.Model
-	GroupID uint `json:"group_id"`
-	UserID  uint `json:"user_id"`
+	GroupID uint   `gorm:"index" json:"group_id"`
+	UserID  uint   `gorm:"index" json:"user_id"`
+	Role    string `gorm:"default:member" json:"role"` // owner, admin, member
 }
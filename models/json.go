@@ -0,0 +1,47 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// ✅ JSON olarak saklanan yapılandırılmış veri
+type JSONMap map[string]interface{}
+
+// ✅ Veritabanına yazarken JSON'a çevir
+func (m JSONMap) Value() (driver.Value, error) {
+	if m == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// ✅ Veritabanından okurken JSON'dan çöz
+func (m *JSONMap) Scan(value interface{}) error {
+	if value == nil {
+		*m = nil
+		return nil
+	}
+
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return errors.New("JSONMap için desteklenmeyen veri tipi")
+	}
+
+	return json.Unmarshal(data, m)
+}
+
+// ✅ PostgreSQL'de jsonb olarak sakla
+func (JSONMap) GormDataType() string {
+	return "jsonb"
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 🔥 Bağlantı önizlemesi önbelleği (URL başına tek kayıt)
+// Getirilemeyen bağlantılar da Failed ile saklanır; böylece aynı adrese tekrar tekrar istek atılmaz.
+type LinkPreview struct {
+	gorm.Model
+	URLHash     string    `gorm:"uniqueIndex;size:64" json:"-"` // URL'nin SHA-256 özeti
+	URL         string    `json:"url"`
+	Title       string    `json:"title,omitempty"`
+	Description string    `json:"description,omitempty"`
+	ImageURL    string    `json:"image_url,omitempty"`
+	SiteName    string    `json:"site_name,omitempty"`
+	FetchedAt   time.Time `json:"fetched_at"`
+	Failed      bool      `gorm:"default:false" json:"-"`
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ✅ Mesaj varlık tipleri
+const (
+	EntityTypeMention    = "mention"     // @kullaniciadi
+	EntityTypeMentionAll = "mention_all" // @all
+)
+
+// ✅ Mesaj içeriğindeki yapılandırılmış varlık
+// Offset ve Length karakter (rune) cinsindendir, "@" işareti dahildir.
+type MessageEntity struct {
+	Type     string `json:"type"`
+	Offset   int    `json:"offset"`
+	Length   int    `json:"length"`
+	UserID   uint   `json:"user_id,omitempty"`
+	Username string `json:"username,omitempty"`
+}
+
+// ✅ JSON olarak saklanan varlık listesi
+type MessageEntities []MessageEntity
+
+// ✅ Veritabanına yazarken JSON'a çevir
+func (e MessageEntities) Value() (driver.Value, error) {
+	if len(e) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// ✅ Veritabanından okurken JSON'dan çöz
+func (e *MessageEntities) Scan(value interface{}) error {
+	if value == nil {
+		*e = nil
+		return nil
+	}
+
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return errors.New("MessageEntities için desteklenmeyen veri tipi")
+	}
+
+	return json.Unmarshal(data, e)
+}
+
+// ✅ PostgreSQL'de jsonb olarak sakla
+func (MessageEntities) GormDataType() string {
+	return "jsonb"
+}
+
+// 🔥 Kullanıcının bir mesajda anılması (mesaj, kullanıcı başına tek kayıt)
+// @all ile anılan her katılımcı için de ayrı kayıt tutulur; okunmamış durumu ReadAt ile izlenir.
+type MessageMention struct {
+	gorm.Model
+	MessageID      uint       `gorm:"uniqueIndex:idx_message_mentions_pair" json:"message_id"`
+	UserID         uint       `gorm:"uniqueIndex:idx_message_mentions_pair;index" json:"user_id"`
+	ConversationID uint       `gorm:"index" json:"conversation_id"`
+	SenderID       uint       `json:"sender_id"`
+	All            bool       `gorm:"default:false" json:"all"` // @all ile mi anıldı
+	ReadAt         *time.Time `json:"read_at"`
+}
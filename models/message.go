@@ -6,12 +6,55 @@ import (
 	"gorm.io/gorm"
 )
 
+// ✅ Mesaj tipleri
+const (
+	MessageTypeUser   = "user"
+	MessageTypeSystem = "system"
+)
+
+// ✅ Sistem mesajı olayları
+const (
+	SystemEventMemberAdded   = "member_added"
+	SystemEventMemberRemoved = "member_removed"
+	SystemEventGroupRenamed  = "group_renamed"
+	SystemEventMessagePinned = "message_pinned"
+)
+
 // 🔥 Mesaj Modeli
 type Message struct {
 	gorm.Model
-	ConversationID uint      `json:"conversation_id"` // Hangi konuşmaya ait
-	SenderID       uint      `json:"sender_id"`       // Mesajı gönderen
-	Content        string    `json:"content"`         // Mesaj içeriği
-	IsRead         bool      `json:"is_read"`         // Okundu bilgisi
-	ReadAt         time.Time `json:"read_at"`         // Okunduğu zaman
+	ConversationID uint      `json:"conversation_id"`          // Hangi konuşmaya ait
+	SenderID       uint      `json:"sender_id"`                // Mesajı gönderen (sistem mesajlarında 0)
+	Type           string    `gorm:"default:user" json:"type"` // user, system
+	Event          string    `json:"event,omitempty"`          // Sistem mesajı olayı
+	Payload        JSONMap   `json:"payload,omitempty"`        // Sistem mesajının yapılandırılmış verisi
+	Content        string    `json:"content"`                  // Mesaj içeriği
+	IsRead         bool      `json:"is_read"`                  // Okundu bilgisi
+	ReadAt         time.Time `json:"read_at"`                  // Okunduğu zaman
+
+	// Yanıtlar ve başlıklar (thread)
+	ReplyToID    *uint   `gorm:"index" json:"reply_to_id,omitempty"`    // Alıntılanan mesaj
+	Quote        JSONMap `json:"quote,omitempty"`                       // Alıntının gönderim anındaki kopyası
+	ThreadRootID *uint   `gorm:"index" json:"thread_root_id,omitempty"` // Mesaj bir başlığa aitse kök mesaj
+
+	// Başlık özeti (sadece kök mesajlarda hesaplanır)
+	ReplyCount        int64      `gorm:"-" json:"reply_count,omitempty"`
+	LastReplyAt       *time.Time `gorm:"-" json:"last_reply_at,omitempty"`
+	ThreadUnreadCount int64      `gorm:"-" json:"thread_unread_count,omitempty"`
+
+	Reactions   []ReactionSummary `gorm:"-" json:"reactions,omitempty"`   // Emoji tepkileri
+	Attachments []Attachment      `gorm:"-" json:"attachments,omitempty"` // Dosya ve görsel ekleri
+
+	Entities MessageEntities `json:"entities,omitempty"` // @bahsetmeler
+
+	// İçerikteki ilk bağlantının önizlemesi; mesaj gönderildikten sonra arka planda doldurulur
+	LinkPreview JSONMap `json:"link_preview,omitempty"`
+
+	// Düzenleme bilgisi; önceki sürümler MessageRevision tablosunda tutulur
+	EditedAt  *time.Time `json:"edited_at"`
+	EditCount int        `gorm:"not null;default:0" json:"edit_count"`
+
+	// "Herkesten sil" sonrası mesaj içeriği temizlenir ve bu alanlarla işaretlenir (tombstone)
+	DeletedForEveryoneAt *time.Time `json:"deleted_for_everyone_at,omitempty"`
+	DeletedBy            *uint      `json:"deleted_by,omitempty"`
 }
@@ -0,0 +1,10 @@
+package models
+
+import "gorm.io/gorm"
+
+// ✅ "Benden sil" ile kullanıcıdan gizlenen mesaj
+type MessageHide struct {
+	gorm.Model
+	MessageID uint `gorm:"uniqueIndex:idx_message_hides_pair" json:"message_id"`
+	UserID    uint `gorm:"uniqueIndex:idx_message_hides_pair" json:"user_id"`
+}
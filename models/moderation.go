@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ✅ Moderasyon işlemleri
+const (
+	ModerationActionBan    = "ban"
+	ModerationActionUnban  = "unban"
+	ModerationActionMute   = "mute"
+	ModerationActionUnmute = "unmute"
+	ModerationActionRemove = "remove"
+)
+
+// ✅ Grup yasağı (ExpiresAt boşsa süresiz)
+type GroupBan struct {
+	gorm.Model
+	GroupID   uint       `gorm:"index" json:"group_id"`
+	UserID    uint       `gorm:"index" json:"user_id"`
+	BannedBy  uint       `json:"banned_by"`
+	Reason    string     `json:"reason"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// ✅ Grupta geçici susturma
+type GroupMute struct {
+	gorm.Model
+	GroupID   uint      `gorm:"index" json:"group_id"`
+	UserID    uint      `gorm:"index" json:"user_id"`
+	MutedBy   uint      `json:"muted_by"`
+	Reason    string    `json:"reason"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ✅ Grup moderasyon kaydı
+type ModerationLog struct {
+	gorm.Model
+	GroupID      uint       `gorm:"index" json:"group_id"`
+	ActorID      uint       `json:"actor_id"`
+	TargetUserID uint       `json:"target_user_id"`
+	Action       string     `json:"action"` // ban, unban, mute, unmute, remove
+	Reason       string     `json:"reason"`
+	ExpiresAt    *time.Time `json:"expires_at"`
+}
@@ -0,0 +1,11 @@
+package models
+
+import "gorm.io/gorm"
+
+// 🔥 Konuşmada sabitlenmiş mesaj (mesaj başına tek kayıt)
+type PinnedMessage struct {
+	gorm.Model
+	ConversationID uint `gorm:"index" json:"conversation_id"`
+	MessageID      uint `gorm:"uniqueIndex" json:"message_id"`
+	PinnedBy       uint `json:"pinned_by"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// ✅ Gizlilik seviyeleri
+const (
+	PrivacyEveryone         = "everyone"
+	PrivacyFriends          = "friends"
+	PrivacyFriendsOfFriends = "friends_of_friends"
+	PrivacyNobody           = "nobody"
+)
+
+// ✅ Kullanıcı gizlilik ayarları
+// Kaydı olmayan kullanıcılar için DefaultPrivacySettings geçerlidir.
+type PrivacySettings struct {
+	gorm.Model
+	UserID             uint   `gorm:"uniqueIndex" json:"user_id"`
+	EmailVisibility    string `gorm:"not null;default:friends" json:"email_visibility"`    // E-postayı kim görebilir
+	PhoneVisibility    string `gorm:"not null;default:friends" json:"phone_visibility"`    // Telefonu kim görebilir
+	FriendRequests     string `gorm:"not null;default:everyone" json:"friend_requests"`    // Kim arkadaşlık isteği gönderebilir
+	GroupAdds          string `gorm:"not null;default:everyone" json:"group_adds"`         // Kim gruba ekleyebilir
+	PresenceVisibility string `gorm:"not null;default:friends" json:"presence_visibility"` // Çevrimiçi durumunu kim görebilir
+}
+
+// ✅ Varsayılan gizlilik ayarları
+func DefaultPrivacySettings(userID uint) PrivacySettings {
+	return PrivacySettings{
+		UserID:             userID,
+		EmailVisibility:    PrivacyFriends,
+		PhoneVisibility:    PrivacyFriends,
+		FriendRequests:     PrivacyEveryone,
+		GroupAdds:          PrivacyEveryone,
+		PresenceVisibility: PrivacyFriends,
+	}
+}
+
+// ✅ Geçerli bir gizlilik seviyesi mi?
+func IsValidPrivacyLevel(level string) bool {
+	switch level {
+	case PrivacyEveryone, PrivacyFriends, PrivacyFriendsOfFriends, PrivacyNobody:
+		return true
+	}
+	return false
+}
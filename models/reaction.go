@@ -0,0 +1,18 @@
+package models
+
+import "gorm.io/gorm"
+
+// 🔥 Mesaja verilen emoji tepkisi (mesaj, kullanıcı, emoji başına tek kayıt)
+type MessageReaction struct {
+	gorm.Model
+	MessageID uint   `gorm:"uniqueIndex:idx_message_reactions_unique" json:"message_id"`
+	UserID    uint   `gorm:"uniqueIndex:idx_message_reactions_unique" json:"user_id"`
+	Emoji     string `gorm:"uniqueIndex:idx_message_reactions_unique;size:64" json:"emoji"`
+}
+
+// ✅ Mesajdaki bir emojinin özeti
+type ReactionSummary struct {
+	Emoji       string `json:"emoji"`
+	Count       int64  `json:"count"`
+	ReactedByMe bool   `json:"reacted_by_me"`
+}
@@ -0,0 +1,12 @@
+package models
+
+import "gorm.io/gorm"
+
+// ✅ Mesajın düzenlemeden önceki sürümü
+// CreatedAt, bu sürümün yerini yenisine bıraktığı zamandır.
+type MessageRevision struct {
+	gorm.Model
+	MessageID uint   `gorm:"index" json:"message_id"`
+	Content   string `json:"content"`
+	EditedBy  uint   `json:"edited_by"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ✅ Zamanlanmış mesaj durumları
+const (
+	ScheduledStatusPending  = "pending"
+	ScheduledStatusSent     = "sent"
+	ScheduledStatusFailed   = "failed"
+	ScheduledStatusCanceled = "canceled"
+)
+
+// 🔥 İleri bir zamanda gönderilecek mesaj
+// Zamanı geldiğinde zamanlayıcı mesajı normal gönderim yoluyla gönderir; yetkiler o anda yeniden kontrol edilir.
+type ScheduledMessage struct {
+	gorm.Model
+	UserID         uint       `gorm:"index" json:"user_id"`
+	ConversationID uint       `gorm:"index" json:"conversation_id"`
+	Content        string     `json:"content"`
+	ReplyToID      *uint      `json:"reply_to_id,omitempty"`
+	ThreadRootID   *uint      `json:"thread_root_id,omitempty"`
+	ScheduledAt    time.Time  `gorm:"index" json:"scheduled_at"`
+	Status         string     `gorm:"default:pending;index" json:"status"` // pending, sent, failed, canceled
+	MessageID      *uint      `json:"message_id,omitempty"`                // Gönderildiğinde oluşan mesaj
+	SentAt         *time.Time `json:"sent_at,omitempty"`
+	FailureReason  string     `json:"failure_reason,omitempty"`
+
+	// Yavaş mod veya geçici hata nedeniyle gönderilemeyen mesajlar NextAttemptAt zamanında tekrar denenir
+	Attempts      int        `gorm:"default:0" json:"attempts"`
+	NextAttemptAt *time.Time `gorm:"index" json:"next_attempt_at,omitempty"`
+}
@@ -0,0 +1,9 @@
+package models
+
+import "time"
+
+// ✅ Bir kez çalıştırılan veri göçlerinin kaydı
+type SchemaMigration struct {
+	Name      string    `gorm:"primaryKey;size:100"`
+	AppliedAt time.Time `gorm:"not null"`
+}
@@ -0,0 +1,11 @@
+package models
+
+import "gorm.io/gorm"
+
+// ✅ Kullanıcının başlıktaki okuma konumu
+type ThreadReadState struct {
+	gorm.Model
+	UserID            uint `gorm:"uniqueIndex:idx_thread_read_states_pair" json:"user_id"`
+	RootMessageID     uint `gorm:"uniqueIndex:idx_thread_read_states_pair" json:"root_message_id"`
+	LastReadMessageID uint `gorm:"default:0" json:"last_read_message_id"`
+}
@@ -16,5 +16,9 @@ type User struct {
 	Password       string    `gorm:"not null" json:"password"`
 	Token          string    `json:"token"`
 	TokenExpiresAt time.Time `json:"token_expires_at"`
-}
+	PhoneHash      string    `gorm:"index" json:"-"`                            // E.164 numaranın SHA-256 özeti
+	Discoverable   bool      `gorm:"not null;default:true" json:"discoverable"` // Rehberden numarasıyla bulunabilir mi
 
+	// Son görülme zamanı; gizlilik ayarına göre ayrıca döndürülür
+	LastSeenAt *time.Time `json:"-"`
+}
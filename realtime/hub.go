@@ -0,0 +1,173 @@
+package realtime
+
+import (
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	writeWait      = 10 * time.Second
+	pongWait       = 60 * time.Second
+	pingPeriod     = (pongWait * 9) / 10
+	maxMessageSize = 4096
+	sendBufferSize = 64
+)
+
+// ✅ İstemcilere gönderilen gerçek zamanlı olay
+type Event struct {
+	Type string      `json:"type"`
+	Data interface{} `json:"data"`
+}
+
+// ✅ Bir WebSocket bağlantısı (kullanıcının birden fazla bağlantısı olabilir)
+type Client struct {
+	UserID uint
+	conn   *websocket.Conn
+	send   chan []byte
+}
+
+// ✅ Kullanıcı başına açık bağlantıları tutan merkez
+// Bağlantılar bellekte tutulur, olaylar sadece bu API örneğine bağlı istemcilere ulaşır.
+type Hub struct {
+	mu      sync.RWMutex
+	clients map[uint]map[*Client]bool
+}
+
+// 🔥 Varsayılan merkez
+var DefaultHub = NewHub()
+
+// ✅ Yeni merkez oluştur
+func NewHub() *Hub {
+	return &Hub{clients: make(map[uint]map[*Client]bool)}
+}
+
+func (h *Hub) register(client *Client) {
+	h.mu.Lock()
+	if h.clients[client.UserID] == nil {
+		h.clients[client.UserID] = make(map[*Client]bool)
+	}
+	h.clients[client.UserID][client] = true
+	h.mu.Unlock()
+
+	Presence.Connected(client.UserID)
+}
+
+func (h *Hub) unregister(client *Client) {
+	h.mu.Lock()
+	if connections, ok := h.clients[client.UserID]; ok && connections[client] {
+		delete(connections, client)
+		close(client.send)
+		if len(connections) == 0 {
+			delete(h.clients, client.UserID)
+		}
+	}
+	h.mu.Unlock()
+
+	Presence.Disconnected(client.UserID)
+}
+
+// ✅ Kullanıcının açık bağlantısı var mı?
+func (h *Hub) IsConnected(userID uint) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.clients[userID]) > 0
+}
+
+// 🔥 Kullanıcının tüm bağlantılarına olay gönder
+func (h *Hub) SendToUser(userID uint, event Event) {
+	h.SendToUsers([]uint{userID}, event)
+}
+
+// 🔥 Birden fazla kullanıcıya olay gönder (olay bir kez JSON'a çevrilir)
+func (h *Hub) SendToUsers(userIDs []uint, event Event) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Println("Hata: Olay JSON'a çevrilemedi -", err)
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, userID := range userIDs {
+		for client := range h.clients[userID] {
+			select {
+			case client.send <- payload:
+			default:
+				// Yavaş istemcinin tamponu doluysa olay atlanır
+			}
+		}
+	}
+}
+
+// 🔥 WebSocket bağlantısını merkeze kaydet ve kapanana kadar çalıştır
+// Gelen her mesaj onMessage ile işlenir.
+func (h *Hub) Serve(conn *websocket.Conn, userID uint, onMessage func(client *Client, message []byte)) {
+	client := &Client{UserID: userID, conn: conn, send: make(chan []byte, sendBufferSize)}
+	h.register(client)
+
+	go client.writePump()
+	client.readPump(onMessage)
+	h.unregister(client)
+}
+
+// ✅ Sadece bu bağlantıya olay gönder
+func (c *Client) Send(event Event) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	select {
+	case c.send <- payload:
+	default:
+	}
+}
+
+func (c *Client) readPump(onMessage func(client *Client, message []byte)) {
+	defer c.conn.Close()
+
+	c.conn.SetReadLimit(maxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, message, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		Presence.Touch(c.UserID)
+		onMessage(c, message)
+	}
+}
+
+func (c *Client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case message, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := c.conn.WriteMessage(websocket.TextMessage, message); err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package realtime
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"arcurachat_api/database"
+	"arcurachat_api/models"
+	"arcurachat_api/utils"
+
+	"gorm.io/gorm"
+)
+
+// ✅ Çevrimiçi durumları
+const (
+	StatusOnline  = "online"
+	StatusAway    = "away"
+	StatusOffline = "offline"
+)
+
+// ✅ Çevrimiçi durumu olay tipi
+const EventPresence = "presence"
+
+var (
+	// Bağlı ama bu süredir hareketsiz kullanıcı "away" sayılır
+	awayAfter = utils.GetEnvDuration("PRESENCE_AWAY_AFTER", 5*time.Minute)
+	// WebSocket'i olmayan, sadece API kullanan istemci bu süre boyunca çevrimiçi sayılır
+	apiActivityWindow = utils.GetEnvDuration("PRESENCE_API_WINDOW", 2*time.Minute)
+	// last_seen_at değerlerinin veritabanına yazılma aralığı
+	flushInterval = utils.GetEnvDuration("PRESENCE_FLUSH_INTERVAL", time.Minute)
+)
+
+// ✅ Kullanıcının anlık durumu
+type PresenceInfo struct {
+	UserID     uint       `json:"user_id"`
+	Status     string     `json:"status"`
+	LastSeenAt *time.Time `json:"last_seen_at"`
+}
+
+// ✅ Bellek içi çevrimiçi durum takipçisi
+// Her istekte veritabanına yazmamak için son hareket zamanları bellekte tutulur ve
+// last_seen_at periyodik olarak toplu şekilde kaydedilir.
+type PresenceTracker struct {
+	mu           sync.Mutex
+	connections  map[uint]int
+	lastActivity map[uint]time.Time
+	lastStatus   map[uint]string
+	dirty        map[uint]time.Time
+	subscribers  map[uint]map[uint]bool // takip edilen -> takip edenler
+	following    map[uint]map[uint]bool // takip eden -> takip edilenler
+}
+
+// 🔥 Varsayılan takipçi
+var Presence = NewPresenceTracker()
+
+// ✅ Yeni takipçi oluştur
+func NewPresenceTracker() *PresenceTracker {
+	return &PresenceTracker{
+		connections:  make(map[uint]int),
+		lastActivity: make(map[uint]time.Time),
+		lastStatus:   make(map[uint]string),
+		dirty:        make(map[uint]time.Time),
+		subscribers:  make(map[uint]map[uint]bool),
+		following:    make(map[uint]map[uint]bool),
+	}
+}
+
+// ✅ API veya WebSocket hareketini kaydet
+func (p *PresenceTracker) Touch(userID uint) {
+	p.mu.Lock()
+	now := time.Now()
+	p.lastActivity[userID] = now
+	p.dirty[userID] = now
+	change := p.statusChangeLocked(userID, now)
+	p.mu.Unlock()
+
+	p.publish(change)
+}
+
+// ✅ Yeni WebSocket bağlantısı
+func (p *PresenceTracker) Connected(userID uint) {
+	p.mu.Lock()
+	now := time.Now()
+	p.connections[userID]++
+	p.lastActivity[userID] = now
+	p.dirty[userID] = now
+	change := p.statusChangeLocked(userID, now)
+	p.mu.Unlock()
+
+	p.publish(change)
+}
+
+// ✅ WebSocket bağlantısı kapandı
+func (p *PresenceTracker) Disconnected(userID uint) {
+	p.mu.Lock()
+	now := time.Now()
+	p.connections[userID]--
+	if p.connections[userID] <= 0 {
+		delete(p.connections, userID)
+		p.dirty[userID] = now
+		p.unsubscribeLocked(userID)
+	}
+	change := p.statusChangeLocked(userID, now)
+	p.mu.Unlock()
+
+	p.publish(change)
+}
+
+// ✅ Kullanıcının anlık durumu
+func (p *PresenceTracker) Status(userID uint) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.statusLocked(userID, time.Now())
+}
+
+// ✅ Bellekteki son hareket zamanı (yoksa false)
+func (p *PresenceTracker) LastActivity(userID uint) (time.Time, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	at, ok := p.lastActivity[userID]
+	return at, ok
+}
+
+// 🔥 Kullanıcıların durum değişikliklerine abone ol
+// Gizlilik kontrolü çağıran tarafından yapılmış olmalıdır.
+func (p *PresenceTracker) Subscribe(subscriberID uint, userIDs []uint) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.following[subscriberID] == nil {
+		p.following[subscriberID] = make(map[uint]bool)
+	}
+	for _, userID := range userIDs {
+		if p.subscribers[userID] == nil {
+			p.subscribers[userID] = make(map[uint]bool)
+		}
+		p.subscribers[userID][subscriberID] = true
+		p.following[subscriberID][userID] = true
+	}
+}
+
+// ✅ Kullanıcının durumuna abone olanlar
+func (p *PresenceTracker) Subscribers(userID uint) []uint {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	subscribers := make([]uint, 0, len(p.subscribers[userID]))
+	for subscriberID := range p.subscribers[userID] {
+		subscribers = append(subscribers, subscriberID)
+	}
+	return subscribers
+}
+
+// ✅ Abonelerin kullanıcıya olan aboneliğini kaldır
+// Gizlilik ayarı daraltıldığında veya engellendiğinde çağrılır.
+func (p *PresenceTracker) Unsubscribe(userID uint, subscriberIDs []uint) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, subscriberID := range subscriberIDs {
+		delete(p.subscribers[userID], subscriberID)
+		delete(p.following[subscriberID], userID)
+		if len(p.following[subscriberID]) == 0 {
+			delete(p.following, subscriberID)
+		}
+	}
+	if len(p.subscribers[userID]) == 0 {
+		delete(p.subscribers, userID)
+	}
+}
+
+// ✅ Aboneliklerden çık (kilit altında çağrılır)
+func (p *PresenceTracker) unsubscribeLocked(subscriberID uint) {
+	for userID := range p.following[subscriberID] {
+		delete(p.subscribers[userID], subscriberID)
+		if len(p.subscribers[userID]) == 0 {
+			delete(p.subscribers, userID)
+		}
+	}
+	delete(p.following, subscriberID)
+}
+
+func (p *PresenceTracker) statusLocked(userID uint, now time.Time) string {
+	lastActivity, active := p.lastActivity[userID]
+	if p.connections[userID] > 0 {
+		if active && now.Sub(lastActivity) < awayAfter {
+			return StatusOnline
+		}
+		return StatusAway
+	}
+	if active && now.Sub(lastActivity) < apiActivityWindow {
+		return StatusOnline
+	}
+	return StatusOffline
+}
+
+type presenceChange struct {
+	info        PresenceInfo
+	subscribers []uint
+}
+
+// Durum değiştiyse abonelere gönderilecek olayı hazırla (kilit altında çağrılır)
+func (p *PresenceTracker) statusChangeLocked(userID uint, now time.Time) *presenceChange {
+	status := p.statusLocked(userID, now)
+	if p.lastStatus[userID] == status {
+		return nil
+	}
+	p.lastStatus[userID] = status
+	if status == StatusOffline {
+		delete(p.lastStatus, userID)
+	}
+
+	change := &presenceChange{info: PresenceInfo{UserID: userID, Status: status}}
+	if lastActivity, ok := p.lastActivity[userID]; ok {
+		change.info.LastSeenAt = &lastActivity
+	}
+	for subscriberID := range p.subscribers[userID] {
+		change.subscribers = append(change.subscribers, subscriberID)
+	}
+	return change
+}
+
+func (p *PresenceTracker) publish(change *presenceChange) {
+	if change == nil || len(change.subscribers) == 0 {
+		return
+	}
+	DefaultHub.SendToUsers(change.subscribers, Event{Type: EventPresence, Data: change.info})
+}
+
+// 🔥 Arka plan döngüsü: away/offline geçişlerini yayınla ve last_seen_at'i kaydet
+func (p *PresenceTracker) Run() {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		p.sweep()
+		p.flush()
+	}
+}
+
+// Zamanla oluşan durum geçişlerini bul ve yayınla
+func (p *PresenceTracker) sweep() {
+	p.mu.Lock()
+	now := time.Now()
+	var changes []*presenceChange
+	for userID := range p.lastStatus {
+		if change := p.statusChangeLocked(userID, now); change != nil {
+			changes = append(changes, change)
+		}
+	}
+	// Çevrimdışı ve bağlantısı olmayan kullanıcıların hareket kaydını bellekten at
+	for userID, at := range p.lastActivity {
+		if p.connections[userID] == 0 && now.Sub(at) > apiActivityWindow && p.dirty[userID].IsZero() {
+			delete(p.lastActivity, userID)
+		}
+	}
+	p.mu.Unlock()
+
+	for _, change := range changes {
+		p.publish(change)
+	}
+}
+
+// Bekleyen last_seen_at değerlerini tek transaction'da yaz
+func (p *PresenceTracker) flush() {
+	p.mu.Lock()
+	pending := p.dirty
+	p.dirty = make(map[uint]time.Time)
+	p.mu.Unlock()
+
+	if len(pending) == 0 || database.DB == nil {
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		for userID, at := range pending {
+			if err := tx.Model(&models.User{}).Where("id = ?", userID).Update("last_seen_at", at).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Println("Hata: last_seen_at kaydedilemedi -", err)
+	}
+}
@@ -0,0 +1,130 @@
+package realtime
+
+import (
+	"sync"
+	"time"
+
+	"arcurachat_api/utils"
+)
+
+// ✅ Geçici konuşma sinyalleri (veritabanına yazılmaz)
+const (
+	SignalTyping    = "typing"
+	SignalRecording = "recording_audio"
+	SignalStop      = "stop"
+)
+
+// ✅ Konuşma sinyali olay tipi
+const EventSignal = "conversation.signal"
+
+var (
+	// Yenilenmeyen sinyal bu süre sonunda kendiliğinden düşer
+	signalTTL = utils.GetEnvDuration("SIGNAL_TTL", 6*time.Second)
+	// Aynı sinyal bu süreden sık tekrar yayınlanmaz
+	signalThrottle = utils.GetEnvDuration("SIGNAL_THROTTLE", 2*time.Second)
+)
+
+// ✅ Geçerli sinyal tipi mi?
+func IsValidSignal(signalType string) bool {
+	switch signalType {
+	case SignalTyping, SignalRecording, SignalStop:
+		return true
+	}
+	return false
+}
+
+// ✅ Konuşmadaki aktif sinyal
+type Signal struct {
+	ConversationID uint      `json:"conversation_id"`
+	UserID         uint      `json:"user_id"`
+	Type           string    `json:"type"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+type signalState struct {
+	signal      Signal
+	broadcastAt time.Time
+}
+
+// ✅ Bellek içi sinyal deposu
+type SignalTracker struct {
+	mu      sync.Mutex
+	signals map[uint]map[uint]*signalState // konuşma -> kullanıcı -> sinyal
+}
+
+// 🔥 Varsayılan sinyal deposu
+var Signals = &SignalTracker{signals: make(map[uint]map[uint]*signalState)}
+
+// 🔥 Sinyali kaydet; yayınlanması gerekiyorsa true döner
+// Aynı tip sinyal throttle süresi içinde tekrarlanırsa sadece süresi uzatılır.
+func (t *SignalTracker) Set(conversationID, userID uint, signalType string) (Signal, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	t.pruneLocked(conversationID, now)
+	current := t.signals[conversationID][userID]
+
+	if signalType == SignalStop {
+		if current == nil {
+			return Signal{}, false
+		}
+		delete(t.signals[conversationID], userID)
+		return Signal{ConversationID: conversationID, UserID: userID, Type: SignalStop, ExpiresAt: now}, true
+	}
+
+	if current != nil && current.signal.Type == signalType && now.Sub(current.broadcastAt) < signalThrottle {
+		current.signal.ExpiresAt = now.Add(signalTTL)
+		return current.signal, false
+	}
+
+	if t.signals[conversationID] == nil {
+		t.signals[conversationID] = make(map[uint]*signalState)
+	}
+	state := &signalState{
+		signal:      Signal{ConversationID: conversationID, UserID: userID, Type: signalType, ExpiresAt: now.Add(signalTTL)},
+		broadcastAt: now,
+	}
+	t.signals[conversationID][userID] = state
+	return state.signal, true
+}
+
+// ✅ Konuşmadaki süresi dolmamış sinyaller
+func (t *SignalTracker) Active(conversationID uint) []Signal {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.pruneLocked(conversationID, time.Now())
+	result := []Signal{}
+	for _, state := range t.signals[conversationID] {
+		result = append(result, state.signal)
+	}
+	return result
+}
+
+// Süresi dolan sinyalleri at (kilit altında çağrılır)
+func (t *SignalTracker) pruneLocked(conversationID uint, now time.Time) {
+	for userID, state := range t.signals[conversationID] {
+		if now.After(state.signal.ExpiresAt) {
+			delete(t.signals[conversationID], userID)
+		}
+	}
+	if len(t.signals[conversationID]) == 0 {
+		delete(t.signals, conversationID)
+	}
+}
+
+// 🔥 Arka plan döngüsü: okunmayan konuşmalardaki süresi dolmuş sinyalleri temizle
+func (t *SignalTracker) Run() {
+	ticker := time.NewTicker(signalTTL)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		t.mu.Lock()
+		now := time.Now()
+		for conversationID := range t.signals {
+			t.pruneLocked(conversationID, now)
+		}
+		t.mu.Unlock()
+	}
+}
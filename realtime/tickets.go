@@ -0,0 +1,68 @@
+package realtime
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"arcurachat_api/utils"
+)
+
+// Bağlantı biletinin geçerlilik süresi
+var ticketTTL = utils.GetEnvDuration("WS_TICKET_TTL", 30*time.Second)
+
+type ticket struct {
+	userID    uint
+	expiresAt time.Time
+}
+
+// ✅ Tek kullanımlık WebSocket bağlantı biletleri
+// Tarayıcılar WebSocket isteğine header ekleyemez; uzun ömürlü JWT'nin URL'ye (ve erişim loglarına)
+// düşmemesi için kimliği doğrulanmış istemci önce kısa ömürlü bir bilet alır.
+type TicketStore struct {
+	mu      sync.Mutex
+	tickets map[string]ticket
+}
+
+// 🔥 Varsayılan bilet deposu
+var Tickets = &TicketStore{tickets: make(map[string]ticket)}
+
+// ✅ Kullanıcı için yeni bilet üret
+func (s *TicketStore) Issue(userID uint) (string, time.Time, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", time.Time{}, err
+	}
+	value := hex.EncodeToString(buf)
+	expiresAt := time.Now().Add(ticketTTL)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Süresi dolan biletleri temizle
+	now := time.Now()
+	for key, t := range s.tickets {
+		if now.After(t.expiresAt) {
+			delete(s.tickets, key)
+		}
+	}
+	s.tickets[value] = ticket{userID: userID, expiresAt: expiresAt}
+	return value, expiresAt, nil
+}
+
+// ✅ Bileti kullan; bilet geçerliyse silinir ve sahibinin ID'si döner
+func (s *TicketStore) Redeem(value string) (uint, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tickets[value]
+	if !ok {
+		return 0, false
+	}
+	delete(s.tickets, value)
+	if time.Now().After(t.expiresAt) {
+		return 0, false
+	}
+	return t.userID, true
+}
@@ -0,0 +1,496 @@
+package routes
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"arcurachat_api/database"
+	"arcurachat_api/models"
+	"arcurachat_api/storage"
+	"arcurachat_api/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// Tek dosya için en büyük boyut (bayt)
+	maxUploadSize = int64(utils.GetEnvInt("MAX_UPLOAD_SIZE", 25<<20))
+	// Kullanıcı başına toplam depolama kotası (bayt)
+	userStorageQuota = int64(utils.GetEnvInt("USER_STORAGE_QUOTA", 1<<30))
+	// İmzalı indirme bağlantılarının geçerlilik süresi
+	attachmentURLTTL = utils.GetEnvDuration("ATTACHMENT_URL_TTL", 5*time.Minute)
+	// Mesaja bağlanmayan eklerin silinmeden önce bekletileceği süre
+	unlinkedAttachmentTTL = utils.GetEnvDuration("UNLINKED_ATTACHMENT_TTL", 24*time.Hour)
+	// Bağlanmamış ek temizliğinin çalışma aralığı
+	attachmentCleanupInterval = utils.GetEnvDuration("ATTACHMENT_CLEANUP_INTERVAL", time.Hour)
+)
+
+// Bir mesaja eklenebilecek en fazla dosya sayısı
+const maxAttachmentsPerMessage = 10
+
+var (
+	errInvalidAttachments = errors.New("geçersiz ek")
+	errStorageQuotaFull   = errors.New("depolama kotası doldu")
+)
+
+// ✅ İzin verilen içerik tipleri ve ek türleri
+// Tarayıcıda çalıştırılabilecek tipler (HTML, SVG vb.) kabul edilmez.
+var allowedContentTypes = map[string]string{
+	"image/jpeg":         models.AttachmentKindImage,
+	"image/png":          models.AttachmentKindImage,
+	"image/gif":          models.AttachmentKindImage,
+	"image/webp":         models.AttachmentKindImage,
+	"video/mp4":          models.AttachmentKindVideo,
+	"video/webm":         models.AttachmentKindVideo,
+	"audio/mpeg":         models.AttachmentKindAudio,
+	"audio/ogg":          models.AttachmentKindAudio,
+	"audio/wave":         models.AttachmentKindAudio,
+	"audio/aiff":         models.AttachmentKindAudio,
+	"application/ogg":    models.AttachmentKindAudio,
+	"application/pdf":    models.AttachmentKindFile,
+	"application/zip":    models.AttachmentKindFile,
+	"application/x-gzip": models.AttachmentKindFile,
+	"text/plain":         models.AttachmentKindFile,
+}
+
+// ✅ Dosya adını güvenli hale getir (dizin ve kontrol karakterleri atılır)
+func sanitizeFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	if runes := []rune(name); len(runes) > 255 {
+		name = string(runes[:255])
+	}
+	if name == "" || name == "." || name == "/" {
+		return "dosya"
+	}
+	return name
+}
+
+// ✅ Kullanıcının kullandığı toplam depolama
+func storageUsage(db *gorm.DB, userID uint) int64 {
+	var used int64
+	db.Model(&models.Attachment{}).
+		Where("uploader_id = ?", userID).
+		Select("COALESCE(SUM(size), 0)").
+		Scan(&used)
+	return used
+}
+
+// ✅ Kullanıcı eki görebilir mi?
+// Mesaja bağlanmamış ekleri sadece yükleyen, bağlı ekleri mesajı görebilen katılımcılar görür.
+func canAccessAttachment(attachment models.Attachment, userID uint) bool {
+	if attachment.MessageID == nil {
+		return attachment.UploaderID == userID
+	}
+
+	var message models.Message
+	if err := database.DB.First(&message, *attachment.MessageID).Error; err != nil {
+		return false
+	}
+	return message.DeletedForEveryoneAt == nil && canViewMessage(message, userID)
+}
+
+// 🔥 Yüklenmiş ekleri mesaja bağla
+// Ekler gönderene ait, aynı konuşmaya yüklenmiş ve henüz bir mesaja bağlanmamış olmalıdır.
+func linkAttachments(tx *gorm.DB, message models.Message, attachmentIDs []uint) error {
+	if len(attachmentIDs) == 0 {
+		return nil
+	}
+
+	result := tx.Model(&models.Attachment{}).
+		Where("id IN ? AND uploader_id = ? AND conversation_id = ? AND message_id IS NULL", attachmentIDs, message.SenderID, message.ConversationID).
+		Update("message_id", message.ID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected != int64(len(attachmentIDs)) {
+		return errInvalidAttachments
+	}
+	return nil
+}
+
+// ✅ Mesajlara eklerini ekle
+func attachAttachments(messages []models.Message) {
+	if len(messages) == 0 {
+		return
+	}
+
+	ids := make([]uint, 0, len(messages))
+	byID := make(map[uint]int, len(messages))
+	for i, message := range messages {
+		ids = append(ids, message.ID)
+		byID[message.ID] = i
+	}
+
+	var attachments []models.Attachment
+	database.DB.Where("message_id IN ?", ids).Order("id ASC").Find(&attachments)
+	attachThumbnails(attachments)
+	for _, attachment := range attachments {
+		i := byID[*attachment.MessageID]
+		messages[i].Attachments = append(messages[i].Attachments, attachment)
+	}
+}
+
+// ✅ Silinen mesajın eklerini kaldır
+// Kayıtlar hemen silinir, dosyalar arka planda depolamadan temizlenir.
+func purgeAttachments(tx *gorm.DB, messageID uint) error {
+	var attachments []models.Attachment
+	if err := tx.Where("message_id = ?", messageID).Find(&attachments).Error; err != nil {
+		return err
+	}
+	if len(attachments) == 0 {
+		return nil
+	}
+	keys, err := deleteAttachmentRows(tx, attachments)
+	if err != nil {
+		return err
+	}
+
+	go deleteStoredFiles(keys)
+	return nil
+}
+
+// ✅ Ek ve küçük görsel kayıtlarını sil, depolamadan silinecek anahtarları döndür
+func deleteAttachmentRows(tx *gorm.DB, attachments []models.Attachment) ([]string, error) {
+	ids := make([]uint, 0, len(attachments))
+	keys := make([]string, 0, len(attachments))
+	for _, attachment := range attachments {
+		ids = append(ids, attachment.ID)
+		keys = append(keys, attachment.StorageKey)
+	}
+
+	var thumbnails []models.AttachmentThumbnail
+	if err := tx.Where("attachment_id IN ?", ids).Find(&thumbnails).Error; err != nil {
+		return nil, err
+	}
+	for _, thumbnail := range thumbnails {
+		keys = append(keys, thumbnail.StorageKey)
+	}
+
+	if err := tx.Unscoped().Where("attachment_id IN ?", ids).Delete(&models.AttachmentThumbnail{}).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Unscoped().Delete(&attachments).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// ✅ Dosyaları depolamadan sil (hatalar sadece loglanır)
+func deleteStoredFiles(keys []string) {
+	for _, key := range keys {
+		if err := storage.Default.Delete(context.Background(), key); err != nil {
+			log.Println("Hata: Ek dosyası silinemedi -", err)
+		}
+	}
+}
+
+// 🔥 Mesaja bağlanmadan bekleyen eskimiş ekleri temizle
+// Yüklenip gönderilmeyen dosyalar kotayı ve depolamayı boş yere doldurmasın diye belirli aralıklarla silinir.
+func RunAttachmentCleanup() {
+	ticker := time.NewTicker(attachmentCleanupInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		cleanupUnlinkedAttachments()
+	}
+}
+
+// ✅ Süresi dolmuş bağlanmamış ekleri sil
+// Satırlar kilitlenerek alınır; aynı anda mesaja bağlanan ek silinmez.
+func cleanupUnlinkedAttachments() {
+	var keys []string
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var attachments []models.Attachment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("message_id IS NULL AND created_at < ?", time.Now().Add(-unlinkedAttachmentTTL)).
+			Order("id").Limit(500).
+			Find(&attachments).Error; err != nil {
+			return err
+		}
+		if len(attachments) == 0 {
+			return nil
+		}
+
+		var err error
+		keys, err = deleteAttachmentRows(tx, attachments)
+		return err
+	})
+	if err != nil {
+		log.Println("Hata: Bağlanmamış ekler temizlenemedi -", err)
+		return
+	}
+	deleteStoredFiles(keys)
+}
+
+// 🔥 Ek Yükle (POST /attachments, multipart: file, conversation_id)
+func UploadAttachment(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	// Multipart başlıkları için küçük bir pay bırakılır
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxUploadSize+1<<20)
+
+	conversationID, err := strconv.ParseUint(c.PostForm("conversation_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz konuşma ID"})
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("Dosya en fazla %d MB olabilir", maxUploadSize>>20)})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dosya gerekli"})
+		return
+	}
+	if header.Size > maxUploadSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("Dosya en fazla %d MB olabilir", maxUploadSize>>20)})
+		return
+	}
+
+	// 🔥 Sadece mesaj gönderebilen katılımcılar ek yükleyebilir
+	if status, reason := conversationWriteRestriction(uint(conversationID), userID.(uint)); status != 0 {
+		c.JSON(status, gin.H{"error": reason})
+		return
+	}
+
+	// Kota zaten doluysa dosya hiç yüklenmez; kesin kontrol kayıt sırasında yapılır
+	if storageUsage(database.DB, userID.(uint))+header.Size > userStorageQuota {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Depolama kotanız doldu"})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dosya okunamadı"})
+		return
+	}
+	defer file.Close()
+
+	// 🔥 İçerik tipi istemcinin bildirdiğine göre değil, dosyanın ilk baytlarına göre belirlenir
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dosya okunamadı"})
+		return
+	}
+	head = head[:n]
+
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	kind, ok := allowedContentTypes[contentType]
+	if !ok {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "Bu dosya türü desteklenmiyor"})
+		return
+	}
+
+	key, err := storage.NewKey("attachments", userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Dosya yüklenemedi"})
+		return
+	}
+
+	body := io.MultiReader(bytes.NewReader(head), file)
+	if err := storage.Default.Put(c.Request.Context(), key, body, header.Size, contentType); err != nil {
+		log.Println("Hata: Ek depolamaya yazılamadı -", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Dosya yüklenemedi"})
+		return
+	}
+
+	attachment := models.Attachment{
+		UploaderID:     userID.(uint),
+		ConversationID: uint(conversationID),
+		StorageKey:     key,
+		FileName:       sanitizeFileName(header.Filename),
+		ContentType:    contentType,
+		Kind:           kind,
+		Size:           header.Size,
+		Status:         models.AttachmentStatusReady,
+	}
+	// 🔥 Görsel ve videolar arka planda işlenir (küçük görsel, boyut, metaveri temizliği)
+	if needsMediaProcessing(kind) {
+		attachment.Status = models.AttachmentStatusProcessing
+	}
+
+	// 🔥 Kota kontrolü
+	// Eşzamanlı yüklemelerin kotayı birlikte aşmaması için kullanıcı satırı kilitlenir.
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&user, userID.(uint)).Error; err != nil {
+			return err
+		}
+		if storageUsage(tx, userID.(uint))+header.Size > userStorageQuota {
+			return errStorageQuotaFull
+		}
+		return tx.Create(&attachment).Error
+	})
+	if err != nil {
+		storage.Default.Delete(context.Background(), key)
+		if errors.Is(err, errStorageQuotaFull) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Depolama kotanız doldu"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Dosya yüklenemedi"})
+		return
+	}
+	if attachment.Status == models.AttachmentStatusProcessing {
+		enqueueMediaProcessing(attachment.ID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Dosya yüklendi", "data": attachment})
+}
+
+// 🔥 Ek İndirme Bağlantısı (GET /attachments/:id)
+// Kısa süreli imzalı bir bağlantı döner; bağlantı sadece erişim kontrolünden sonra üretilir.
+func GetAttachmentURL(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	var attachment models.Attachment
+	if err := database.DB.First(&attachment, c.Param("id")).Error; err != nil || !canAccessAttachment(attachment, userID.(uint)) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ek bulunamadı"})
+		return
+	}
+
+	// 🔥 Metaverisi (ör. GPS konumu) temizlenmemiş orijinal dosya paylaşılmaz
+	switch attachment.Status {
+	case models.AttachmentStatusProcessing:
+		c.JSON(http.StatusConflict, gin.H{"error": "Ek hâlâ işleniyor"})
+		return
+	case models.AttachmentStatusFailed:
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Ek işlenemedi"})
+		return
+	}
+
+	attachments := []models.Attachment{attachment}
+	attachThumbnails(attachments)
+	attachment = attachments[0]
+
+	url, err := storage.Default.SignedURL(attachment.StorageKey, attachmentURLTTL)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "İndirme bağlantısı oluşturulamadı"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"attachment": attachment,
+			"url":        url,
+			"expires_at": time.Now().Add(attachmentURLTTL),
+		},
+	})
+}
+
+// ✅ Eki Sil (DELETE /attachments/:id)
+// Sadece henüz mesaja bağlanmamış ekler silinebilir; gönderilmiş ekler mesajla birlikte silinir.
+func DeleteAttachment(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	var attachment models.Attachment
+	if err := database.DB.First(&attachment, c.Param("id")).Error; err != nil || attachment.UploaderID != userID.(uint) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ek bulunamadı"})
+		return
+	}
+	if attachment.MessageID != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Gönderilmiş ekler mesaj silinerek kaldırılır"})
+		return
+	}
+
+	keys, err := deleteAttachmentRows(database.DB, []models.Attachment{attachment})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ek silinemedi"})
+		return
+	}
+	deleteStoredFiles(keys)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Ek silindi"})
+}
+
+// 🔥 Yerel Depolamadan Dosya Sun (GET /files/*key?expires=...&signature=...)
+// Kimlik doğrulama imzalı URL ile yapılır; S3 kullanılıyorsa bağlantılar doğrudan S3'e gider.
+func ServeLocalFile(c *gin.Context) {
+	local, ok := storage.Default.(*storage.LocalStorage)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Dosya bulunamadı"})
+		return
+	}
+
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	if !local.Verify(key, c.Query("expires"), c.Query("signature")) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Bağlantı geçersiz veya süresi dolmuş"})
+		return
+	}
+
+	// Anahtar bir eke ya da ekin küçük görseline ait olabilir
+	var attachment models.Attachment
+	size, contentType := int64(0), ""
+	if err := database.DB.Where("storage_key = ?", key).First(&attachment).Error; err == nil {
+		size, contentType = attachment.Size, attachment.ContentType
+	} else {
+		var thumbnail models.AttachmentThumbnail
+		if err := database.DB.Where("storage_key = ?", key).First(&thumbnail).Error; err != nil ||
+			database.DB.First(&attachment, thumbnail.AttachmentID).Error != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Dosya bulunamadı"})
+			return
+		}
+		size, contentType = thumbnail.Size, thumbnail.ContentType
+	}
+
+	file, err := local.Open(c.Request.Context(), key)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Dosya bulunamadı"})
+		return
+	}
+	defer file.Close()
+
+	disposition := "attachment"
+	if attachment.Kind != models.AttachmentKindFile {
+		disposition = "inline"
+	}
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": attachment.FileName}))
+	c.DataFromReader(http.StatusOK, size, contentType, file, nil)
+}
+
+// ✅ Ek route'larını kaydet
+func RegisterAttachmentRoutes(router *gin.Engine) {
+	attachmentRoutes := router.Group("/attachments")
+	attachmentRoutes.Use(AuthMiddleware())
+	{
+		attachmentRoutes.POST("", UploadAttachment)
+		attachmentRoutes.GET("/:id", GetAttachmentURL)
+		attachmentRoutes.DELETE("/:id", DeleteAttachment)
+	}
+
+	router.GET("/files/*key", ServeLocalFile)
+}
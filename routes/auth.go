@@ -6,6 +6,7 @@ import (
 
 	"arcurachat_api/database"
 	"arcurachat_api/models"
+	"arcurachat_api/realtime"
 	"arcurachat_api/utils"
 
 	"github.com/gin-gonic/gin"
@@ -27,6 +28,11 @@ func RegisterRoutes(router *gin.Engine) {
 	userRoutes.PUT("/:id", UpdateUser)    // PUT /users/:id
 	userRoutes.PUT("/:id/password", UpdatePassword) // 🔥 Şifre güncelleme
 	userRoutes.DELETE("/:id", DeleteUser) // DELETE /users/:id
+	userRoutes.GET("/blocked", GetBlockedUsers)     // 🔥 Engellenen kullanıcılar
+	userRoutes.POST("/:id/block", BlockUser)        // 🔥 Kullanıcıyı engelle
+	userRoutes.DELETE("/:id/block", UnblockUser)    // 🔥 Engeli kaldır
+	userRoutes.GET("/:id/privacy", GetPrivacySettings)    // 🔥 Gizlilik ayarları
+	userRoutes.PUT("/:id/privacy", UpdatePrivacySettings) // 🔥 Gizlilik ayarlarını güncelle
 }
 
 // Kullanıcı kayıt fonksiyonu
@@ -45,6 +51,11 @@ func RegisterUser(c *gin.Context) {
 	}
 	input.Password = string(hashedPassword)
 
+	// 🔥 Kişi keşfi için telefon numarasının özetini sakla
+	if e164, err := utils.NormalizePhoneNumber(input.PhoneNumber); err == nil {
+		input.PhoneHash = utils.HashPhoneNumber(e164)
+	}
+
 	// Kullanıcıyı kaydet
 	if err := database.DB.Create(&input).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Kullanıcı oluşturulamadı"})
@@ -127,6 +138,9 @@ func AuthMiddleware() gin.HandlerFunc {
 
 		// ✅ **User ID'yi context'e kaydet**
 		c.Set("userID", userID)
+
+		// ✅ Çevrimiçi durumu için hareketi kaydet (veritabanına toplu yazılır)
+		realtime.Presence.Touch(userID)
 		c.Next()
 	}
 }
@@ -159,26 +173,42 @@ func GetUser(c *gin.Context) {
 
 	id := c.Param("id") // URL'den gelen ID
 
-	// Kullanıcı sadece kendi bilgilerini görüntüleyebilir
-	if id != strconv.Itoa(int(userID.(uint))) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Bu işlemi yapmaya yetkiniz yok"})
+	var user models.User
+	if err := database.DB.First(&user, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Kullanıcı bulunamadı"})
 		return
 	}
 
-	var user models.User
-	if err := database.DB.First(&user, id).Error; err != nil {
+	// 🔥 Engelli kullanıcılar birbirinin profilini göremez
+	if isBlockedBetween(userID.(uint), user.ID) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Kullanıcı bulunamadı"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	profile := gin.H{
 		"id":         user.ID,
 		"username":   user.Username,
 		"first_name": user.FirstName,
 		"last_name":  user.LastName,
-		"email":      user.Email,
-		"phone":      user.PhoneNumber,
-	})
+	}
+
+	// 🔥 E-posta ve telefon gizlilik ayarlarına göre gösterilir
+	privacy := getPrivacySettings(user.ID)
+	if privacyAllows(privacy.EmailVisibility, user.ID, userID.(uint)) {
+		profile["email"] = user.Email
+	}
+	if privacyAllows(privacy.PhoneVisibility, user.ID, userID.(uint)) {
+		profile["phone"] = user.PhoneNumber
+	}
+	if canSeePresence(user.ID, userID.(uint)) {
+		profile["status"] = realtime.Presence.Status(user.ID)
+		profile["last_seen_at"] = user.LastSeenAt
+		if lastActivity, ok := realtime.Presence.LastActivity(user.ID); ok {
+			profile["last_seen_at"] = lastActivity
+		}
+	}
+
+	c.JSON(http.StatusOK, profile)
 }
 
 
@@ -204,10 +234,11 @@ func UpdateUser(c *gin.Context) {
 	}
 
 	var updateData struct {
-		FirstName   string `json:"first_name"`
-		LastName    string `json:"last_name"`
-		Email       string `json:"email"`
-		PhoneNumber string `json:"phone_number"`
+		FirstName    string `json:"first_name"`
+		LastName     string `json:"last_name"`
+		Email        string `json:"email"`
+		PhoneNumber  string `json:"phone_number"`
+		Discoverable *bool  `json:"discoverable"`
 	}
 
 	if err := c.ShouldBindJSON(&updateData); err != nil {
@@ -215,12 +246,27 @@ func UpdateUser(c *gin.Context) {
 		return
 	}
 
-	database.DB.Model(&user).Updates(models.User{
+	userUpdates := models.User{
 		FirstName:   updateData.FirstName,
 		LastName:    updateData.LastName,
 		Email:       updateData.Email,
 		PhoneNumber: updateData.PhoneNumber,
-	})
+	}
+	// 🔥 Geçersiz numara kabul edilmez; aksi halde eski numaranın özetiyle rehber eşleşmesi devam ederdi
+	if updateData.PhoneNumber != "" {
+		e164, err := utils.NormalizePhoneNumber(updateData.PhoneNumber)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz telefon numarası"})
+			return
+		}
+		userUpdates.PhoneHash = utils.HashPhoneNumber(e164)
+	}
+	database.DB.Model(&user).Updates(userUpdates)
+
+	// 🔥 Keşfedilebilirlik false olabileceği için ayrıca güncellenir
+	if updateData.Discoverable != nil {
+		database.DB.Model(&user).Update("discoverable", *updateData.Discoverable)
+	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Kullanıcı bilgileri güncellendi"})
 }
@@ -0,0 +1,151 @@
+package routes
+
+import (
+	"net/http"
+	"strconv"
+
+	"arcurachat_api/database"
+	"arcurachat_api/models"
+	"arcurachat_api/realtime"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ✅ İki kullanıcıdan biri diğerini engellemiş mi?
+func isBlockedBetween(userID, otherUserID uint) bool {
+	var count int64
+	database.DB.Model(&models.UserBlock{}).
+		Where("(blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?)", userID, otherUserID, otherUserID, userID).
+		Count(&count)
+	return count > 0
+}
+
+// ✅ Kullanıcı sorgularından engellenen ve engelleyen kullanıcıları çıkar
+func excludeBlockedUsers(userID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(`users.id NOT IN (
+			SELECT blocked_id FROM user_blocks WHERE blocker_id = ? AND deleted_at IS NULL
+		) AND users.id NOT IN (
+			SELECT blocker_id FROM user_blocks WHERE blocked_id = ? AND deleted_at IS NULL
+		)`, userID, userID)
+	}
+}
+
+// ✅ Birebir konuşmadaki karşı tarafla aralarında engel var mı?
+func isDirectConversationBlocked(conversationID, userID uint) bool {
+	var count int64
+	database.DB.Table("conversation_participants cp").
+		Joins("JOIN conversations c ON c.id = cp.conversation_id").
+		Where("cp.conversation_id = ? AND c.type = ? AND cp.user_id <> ? AND cp.deleted_at IS NULL", conversationID, models.ConversationTypeDirect, userID).
+		Where(`EXISTS (
+			SELECT 1 FROM user_blocks b WHERE b.deleted_at IS NULL
+			AND ((b.blocker_id = ? AND b.blocked_id = cp.user_id) OR (b.blocker_id = cp.user_id AND b.blocked_id = ?))
+		)`, userID, userID).
+		Count(&count)
+	return count > 0
+}
+
+// 🔥 Kullanıcıyı Engelle (POST /users/:id/block)
+// Arkadaşlık sona erer, aradaki bekleyen istekler iptal edilir.
+func BlockUser(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	blockedID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz kullanıcı ID"})
+		return
+	}
+
+	if uint(blockedID) == userID.(uint) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Kendinizi engelleyemezsiniz"})
+		return
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, blockedID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Kullanıcı bulunamadı"})
+		return
+	}
+
+	block := models.UserBlock{BlockerID: userID.(uint), BlockedID: user.ID}
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&block).Error; err != nil {
+			return err
+		}
+
+		if _, err := endFriendship(tx, block.BlockerID, block.BlockedID); err != nil {
+			return err
+		}
+
+		return tx.Model(&models.FriendRequest{}).
+			Where("((sender_id = ? AND receiver_id = ?) OR (sender_id = ? AND receiver_id = ?)) AND status = ?",
+				block.BlockerID, block.BlockedID, block.BlockedID, block.BlockerID, models.FriendRequestPending).
+			Update("status", models.FriendRequestCancelled).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Kullanıcı engellenemedi"})
+		return
+	}
+
+	// 🔥 Engellenen taraflar birbirinin çevrimiçi durumunu artık izleyemez
+	realtime.Presence.Unsubscribe(block.BlockerID, []uint{block.BlockedID})
+	realtime.Presence.Unsubscribe(block.BlockedID, []uint{block.BlockerID})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Kullanıcı engellendi"})
+}
+
+// ✅ Engeli Kaldır (DELETE /users/:id/block)
+func UnblockUser(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	blockedID := c.Param("id")
+
+	result := database.DB.Unscoped().Where("blocker_id = ? AND blocked_id = ?", userID, blockedID).Delete(&models.UserBlock{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Engel kaldırılamadı"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Bu kullanıcıyı engellememişsiniz"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Engel kaldırıldı"})
+}
+
+// ✅ Engellenen Kullanıcılar (GET /users/blocked)
+func GetBlockedUsers(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	var users []struct {
+		ID        uint   `json:"id"`
+		Username  string `json:"username"`
+		FirstName string `json:"first_name"`
+		LastName  string `json:"last_name"`
+	}
+	if err := database.DB.Model(&models.User{}).
+		Select("users.id, users.username, users.first_name, users.last_name").
+		Joins("JOIN user_blocks b ON b.blocked_id = users.id AND b.deleted_at IS NULL").
+		Where("b.blocker_id = ?", userID).
+		Order("b.created_at DESC").
+		Scan(&users).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Engellenen kullanıcılar alınamadı"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"blocked_users": users})
+}
@@ -0,0 +1,95 @@
+package routes
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"arcurachat_api/database"
+	"arcurachat_api/models"
+	"arcurachat_api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxContactHashesPerRequest = 500
+
+var (
+	phoneHashPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+	// 🔥 Rehber yüklemeleri kullanıcı başına sınırlandırılır
+	contactDiscoveryLimiter = utils.NewRateLimiter(
+		utils.GetEnvInt("CONTACT_DISCOVERY_LIMIT", 5),
+		utils.GetEnvDuration("CONTACT_DISCOVERY_WINDOW", time.Hour),
+	)
+)
+
+// 🔥 Rehberden Kişi Keşfi (POST /contacts/discover)
+// İstemci E.164 numaraların SHA-256 özetlerini gönderir; özetler sadece sorguda
+// kullanılır, hiçbir yerde saklanmaz veya loglanmaz.
+func DiscoverContacts(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	if allowed, retryAfter := contactDiscoveryLimiter.Allow(strconv.Itoa(int(userID.(uint)))); !allowed {
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error":       "Çok fazla rehber yüklemesi, daha sonra tekrar deneyin",
+			"retry_after": int(retryAfter.Seconds()) + 1,
+		})
+		return
+	}
+
+	var input struct {
+		Hashes []string `json:"hashes" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz veri"})
+		return
+	}
+
+	if len(input.Hashes) > maxContactHashesPerRequest {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Tek seferde en fazla 500 numara gönderilebilir"})
+		return
+	}
+
+	for _, hash := range input.Hashes {
+		if !phoneHashPattern.MatchString(hash) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Numara özetleri küçük harfli SHA-256 hex olmalıdır"})
+			return
+		}
+	}
+
+	var matches []struct {
+		Hash      string `json:"hash"`
+		ID        uint   `json:"id"`
+		Username  string `json:"username"`
+		FirstName string `json:"first_name"`
+		LastName  string `json:"last_name"`
+	}
+	if len(input.Hashes) > 0 {
+		if err := database.DB.Model(&models.User{}).
+			Select("users.phone_hash AS hash, users.id, users.username, users.first_name, users.last_name").
+			Scopes(excludeBlockedUsers(userID.(uint))).
+			Where("users.phone_hash IN ? AND users.discoverable = ? AND users.id <> ?", input.Hashes, true, userID).
+			Scan(&matches).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Kişiler aranamadı"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"matches": matches})
+}
+
+// ✅ Kişi route'larını kaydet
+func RegisterContactRoutes(router *gin.Engine) {
+	contactRoutes := router.Group("/contacts")
+	contactRoutes.Use(AuthMiddleware())
+	{
+		contactRoutes.POST("/discover", DiscoverContacts)
+	}
+}
@@ -0,0 +1,316 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"arcurachat_api/database"
+	"arcurachat_api/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ✅ Kullanıcının erişebildiği mesajlar
+// Her üyelik dönemi yalnızca katıldığı andan (çıkarıldıysa çıkarıldığı ana kadar)
+// gönderilen mesajları görebilir. "Benden sil" ile gizlenen mesajlar hariç tutulur.
+func accessibleMessages(userID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(`EXISTS (
+			SELECT 1 FROM conversation_participants cp
+			WHERE cp.conversation_id = messages.conversation_id
+			AND cp.user_id = ?
+			AND cp.deleted_at IS NULL
+			AND messages.created_at >= cp.joined_at
+			AND (cp.left_at IS NULL OR messages.created_at <= cp.left_at)
+		) AND NOT EXISTS (
+			SELECT 1 FROM message_hides mh
+			WHERE mh.message_id = messages.id AND mh.user_id = ? AND mh.deleted_at IS NULL
+		)`, userID, userID)
+	}
+}
+
+// ✅ Kullanıcı konuşmanın aktif katılımcısı mı?
+func isActiveParticipant(conversationID, userID uint) bool {
+	var count int64
+	database.DB.Model(&models.ConversationParticipant{}).
+		Where("conversation_id = ? AND user_id = ? AND left_at IS NULL", conversationID, userID).
+		Count(&count)
+	return count > 0
+}
+
+// ✅ Kullanıcı konuşmaya (geçmiş dahil) erişebiliyor mu?
+func hasConversationAccess(conversationID, userID uint) bool {
+	var count int64
+	database.DB.Model(&models.ConversationParticipant{}).
+		Where("conversation_id = ? AND user_id = ?", conversationID, userID).
+		Count(&count)
+	return count > 0
+}
+
+// ✅ Kullanıcı mesajı görebilir mi?
+func canViewMessage(message models.Message, userID uint) bool {
+	var count int64
+	database.DB.Model(&models.Message{}).
+		Scopes(accessibleMessages(userID)).
+		Where("messages.id = ?", message.ID).
+		Count(&count)
+	return count > 0
+}
+
+// 🔥 Kullanıcı konuşmaya içerik (sinyal, ek) gönderebilir mi?
+// Mesaj gönderemeyen (susturulmuş, kanal üyesi, engelli) kullanıcı "yazıyor" gösteremez ve ek yükleyemez;
+// yavaş mod ise sadece mesajları sınırlar.
+func conversationWriteRestriction(conversationID, userID uint) (int, string) {
+	if !isActiveParticipant(conversationID, userID) {
+		return http.StatusForbidden, "Bu konuşmaya erişim yetkiniz yok"
+	}
+	if isDirectConversationBlocked(conversationID, userID) {
+		return http.StatusForbidden, "Bu kullanıcıya mesaj gönderemezsiniz"
+	}
+	if status, reason := groupPostRestriction(conversationID, userID); status == http.StatusForbidden {
+		return status, reason
+	}
+	return 0, ""
+}
+
+// ✅ Konuşmaya katılımcı ekle (zaten aktifse bir şey yapma)
+func addParticipant(tx *gorm.DB, conversationID, userID uint) error {
+	var existing models.ConversationParticipant
+	err := tx.Where("conversation_id = ? AND user_id = ? AND left_at IS NULL", conversationID, userID).First(&existing).Error
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	participant := models.ConversationParticipant{
+		ConversationID: conversationID,
+		UserID:         userID,
+		JoinedAt:       time.Now(),
+	}
+	return tx.Create(&participant).Error
+}
+
+// ✅ Katılımcıyı konuşmadan çıkar (geçmiş kayıt korunur)
+func removeParticipant(tx *gorm.DB, conversationID, userID uint) error {
+	return tx.Model(&models.ConversationParticipant{}).
+		Where("conversation_id = ? AND user_id = ? AND left_at IS NULL", conversationID, userID).
+		Update("left_at", time.Now()).Error
+}
+
+// ✅ Grubun konuşmasını oluştur ve mevcut üyeleri katılımcı yap
+// Konuşması olmayan eski gruplar için de kullanılır.
+func ensureGroupConversation(tx *gorm.DB, group *models.Group) error {
+	if group.ConversationID != 0 {
+		return nil
+	}
+
+	conversation := models.Conversation{
+		Type:    models.ConversationTypeGroup,
+		GroupID: &group.ID,
+	}
+	if err := tx.Create(&conversation).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Model(group).Update("conversation_id", conversation.ID).Error; err != nil {
+		return err
+	}
+
+	if err := addParticipant(tx, conversation.ID, group.OwnerID); err != nil {
+		return err
+	}
+
+	var members []models.GroupMember
+	if err := tx.Where("group_id = ?", group.ID).Find(&members).Error; err != nil {
+		return err
+	}
+	for _, member := range members {
+		if err := addParticipant(tx, conversation.ID, member.UserID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ✅ Birebir konuşmanın çifte özel anahtarı (sıradan bağımsız)
+func directConversationKey(userID, otherUserID uint) string {
+	if userID > otherUserID {
+		userID, otherUserID = otherUserID, userID
+	}
+	return fmt.Sprintf("%d:%d", userID, otherUserID)
+}
+
+// 🔥 Birebir konuşmayı getir, yoksa oluştur
+// direct_key benzersiz olduğundan eşzamanlı istekler aynı çift için ikinci bir konuşma açamaz;
+// çakışan istek diğerinin oluşturduğu konuşmayı kullanır.
+func getOrCreateDirectConversation(tx *gorm.DB, userID, otherUserID uint) (models.Conversation, error) {
+	key := directConversationKey(userID, otherUserID)
+
+	var conversation models.Conversation
+	err := tx.Where("direct_key = ?", key).First(&conversation).Error
+	if err == nil {
+		return conversation, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return conversation, err
+	}
+
+	conversation = models.Conversation{Type: models.ConversationTypeDirect, DirectKey: &key}
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&conversation)
+	if result.Error != nil {
+		return conversation, result.Error
+	}
+	if result.RowsAffected == 0 {
+		conversation = models.Conversation{}
+		err := tx.Where("direct_key = ?", key).First(&conversation).Error
+		return conversation, err
+	}
+
+	if err := addParticipant(tx, conversation.ID, userID); err != nil {
+		return conversation, err
+	}
+	if err := addParticipant(tx, conversation.ID, otherUserID); err != nil {
+		return conversation, err
+	}
+
+	return conversation, nil
+}
+
+// ✅ Birebir konuşmayı kendi transaction'ı içinde getir veya oluştur
+func directConversation(userID, otherUserID uint) (models.Conversation, error) {
+	var conversation models.Conversation
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		conversation, err = getOrCreateDirectConversation(tx, userID, otherUserID)
+		return err
+	})
+	return conversation, err
+}
+
+// 🔥 Birebir Konuşma Başlat (POST /conversations/direct)
+func StartDirectConversation(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	var input struct {
+		UserID uint `json:"user_id" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz veri"})
+		return
+	}
+
+	if input.UserID == userID.(uint) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Kendinizle konuşma başlatamazsınız"})
+		return
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, input.UserID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Kullanıcı bulunamadı"})
+		return
+	}
+
+	if isBlockedBetween(userID.(uint), user.ID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Bu kullanıcıyla konuşma başlatamazsınız"})
+		return
+	}
+
+	conversation, err := directConversation(userID.(uint), input.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Konuşma oluşturulamadı"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": conversation})
+}
+
+// 🔥 Konuşmanın bildirimlerini sessize al (POST /conversations/:id/mute)
+// duration_minutes verilmezse süresiz sessize alınır. Bahsetmeler sessizde de bildirilir.
+func MuteConversation(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	var input struct {
+		DurationMinutes int `json:"duration_minutes"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil || input.DurationMinutes < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz veri"})
+			return
+		}
+	}
+
+	var mutedUntil *time.Time
+	if input.DurationMinutes > 0 {
+		until := time.Now().Add(time.Duration(input.DurationMinutes) * time.Minute)
+		mutedUntil = &until
+	}
+
+	result := database.DB.Model(&models.ConversationParticipant{}).
+		Where("conversation_id = ? AND user_id = ? AND left_at IS NULL", c.Param("id"), userID).
+		Updates(map[string]interface{}{"muted": true, "muted_until": mutedUntil})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Konuşma sessize alınamadı"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Bu konuşmaya erişim yetkiniz yok"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Konuşma sessize alındı", "data": gin.H{"muted": true, "muted_until": mutedUntil}})
+}
+
+// ✅ Konuşmanın sessizini kaldır (DELETE /conversations/:id/mute)
+func UnmuteConversation(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	result := database.DB.Model(&models.ConversationParticipant{}).
+		Where("conversation_id = ? AND user_id = ? AND left_at IS NULL", c.Param("id"), userID).
+		Updates(map[string]interface{}{"muted": false, "muted_until": nil})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Konuşmanın sessizi kaldırılamadı"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Bu konuşmaya erişim yetkiniz yok"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Konuşmanın sessizi kaldırıldı"})
+}
+
+// ✅ Konuşma route'larını kaydet
+func RegisterConversationRoutes(router *gin.Engine) {
+	conversationRoutes := router.Group("/conversations")
+	conversationRoutes.Use(AuthMiddleware())
+	{
+		conversationRoutes.POST("/direct", StartDirectConversation)
+		conversationRoutes.GET("/:id/signals", GetConversationSignals)
+		conversationRoutes.POST("/:id/signals", SendConversationSignal)
+		conversationRoutes.GET("/:id/threads/:message_id", GetThread)
+		conversationRoutes.POST("/:id/threads/:message_id/read", MarkThreadAsRead)
+		conversationRoutes.POST("/:id/mute", MuteConversation)
+		conversationRoutes.GET("/:id/pins", GetPinnedMessages)
+		conversationRoutes.DELETE("/:id/mute", UnmuteConversation)
+	}
+}
@@ -1,14 +1,78 @@
 package routes
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"arcurachat_api/database"
 	"arcurachat_api/models"
+	"arcurachat_api/utils"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// 🔥 Bekleyen isteklerin geçerlilik süresi ve reddedilen istekten sonra bekleme süresi
+var (
+	friendRequestExpiry   = utils.GetEnvDuration("FRIEND_REQUEST_EXPIRY", 30*24*time.Hour)
+	friendRequestCooldown = utils.GetEnvDuration("FRIEND_REQUEST_COOLDOWN", 72*time.Hour)
+)
+
+// ✅ Kullanıcının süresi dolan bekleyen isteklerini "expired" olarak işaretle
+// Sadece kullanıcının gönderdiği veya aldığı istekler güncellenir, tüm tablo taranmaz.
+func expireFriendRequests(userID uint) {
+	database.DB.Model(&models.FriendRequest{}).
+		Where("(sender_id = ? OR receiver_id = ?) AND status = ? AND created_at < ?",
+			userID, userID, models.FriendRequestPending, time.Now().Add(-friendRequestExpiry)).
+		Update("status", models.FriendRequestExpired)
+}
+
+var errFriendRequestNotPending = errors.New("arkadaşlık isteği beklemede değil")
+
+// 🔥 Arkadaşlık isteğini kabul et: istek durumu, iki yönlü arkadaşlık ve birebir konuşma tek transaction'da
+func acceptFriendRequest(tx *gorm.DB, friendRequest models.FriendRequest) error {
+	result := tx.Model(&models.FriendRequest{}).
+		Where("id = ? AND status = ?", friendRequest.ID, models.FriendRequestPending).
+		Update("status", models.FriendRequestAccepted)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errFriendRequestNotPending
+	}
+
+	// Karşı yönde bekleyen bir istek varsa o da kabul edilmiş sayılır
+	if err := tx.Model(&models.FriendRequest{}).
+		Where("sender_id = ? AND receiver_id = ? AND status = ?", friendRequest.ReceiverID, friendRequest.SenderID, models.FriendRequestPending).
+		Update("status", models.FriendRequestAccepted).Error; err != nil {
+		return err
+	}
+
+	// Çift yönlü arkadaşlık kayıtları
+	friendships := []models.Friendship{
+		{UserID: friendRequest.SenderID, FriendID: friendRequest.ReceiverID},
+		{UserID: friendRequest.ReceiverID, FriendID: friendRequest.SenderID},
+	}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&friendships).Error; err != nil {
+		return err
+	}
+
+	// 🔥 Arkadaşlar arasında birebir konuşmayı hazırla
+	_, err := getOrCreateDirectConversation(tx, friendRequest.SenderID, friendRequest.ReceiverID)
+	return err
+}
+
+// 🔥 Arkadaşlığı iki yönde birden sonlandır
+func endFriendship(tx *gorm.DB, userID, friendID uint) (int64, error) {
+	result := tx.Unscoped().
+		Where("(user_id = ? AND friend_id = ?) OR (user_id = ? AND friend_id = ?)", userID, friendID, friendID, userID).
+		Delete(&models.Friendship{})
+	return result.RowsAffected, result.Error
+}
+
 // ✅ Arkadaşlık isteği gönder
 func SendFriendRequest(c *gin.Context) {
 	userID, exists := c.Get("userID")
@@ -26,6 +90,29 @@ func SendFriendRequest(c *gin.Context) {
 		return
 	}
 
+	if input.ReceiverID == userID.(uint) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Kendinize arkadaşlık isteği gönderemezsiniz"})
+		return
+	}
+
+	var receiver models.User
+	if err := database.DB.First(&receiver, input.ReceiverID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Kullanıcı bulunamadı"})
+		return
+	}
+
+	// 🔥 Aralarında engel varsa istek gönderilemez
+	if isBlockedBetween(userID.(uint), receiver.ID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Bu kullanıcıya arkadaşlık isteği gönderemezsiniz"})
+		return
+	}
+
+	// 🔥 Alıcının gizlilik ayarı kimlerin istek gönderebileceğini belirler
+	if !privacyAllows(getPrivacySettings(receiver.ID).FriendRequests, receiver.ID, userID.(uint)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Bu kullanıcı arkadaşlık isteği kabul etmiyor"})
+		return
+	}
+
 	// Kullanıcı zaten arkadaş mı?
 	var existingFriendship models.Friendship
 	if err := database.DB.Where("user_id = ? AND friend_id = ?", userID, input.ReceiverID).First(&existingFriendship).Error; err == nil {
@@ -33,17 +120,44 @@ func SendFriendRequest(c *gin.Context) {
 		return
 	}
 
+	expireFriendRequests(userID.(uint))
+
 	// Önceden istek gönderilmiş mi?
 	var existingRequest models.FriendRequest
-	if err := database.DB.Where("sender_id = ? AND receiver_id = ? AND status = 'pending'", userID, input.ReceiverID).First(&existingRequest).Error; err == nil {
+	if err := database.DB.Where("sender_id = ? AND receiver_id = ? AND status = ?", userID, input.ReceiverID, models.FriendRequestPending).First(&existingRequest).Error; err == nil {
 		c.JSON(http.StatusConflict, gin.H{"error": "Zaten bekleyen bir arkadaşlık isteğiniz var"})
 		return
 	}
 
+	// 🔥 Karşı taraf da istek göndermişse otomatik kabul et
+	var reverseRequest models.FriendRequest
+	if err := database.DB.Where("sender_id = ? AND receiver_id = ? AND status = ?", input.ReceiverID, userID, models.FriendRequestPending).First(&reverseRequest).Error; err == nil {
+		if err := database.DB.Transaction(func(tx *gorm.DB) error {
+			return acceptFriendRequest(tx, reverseRequest)
+		}); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Arkadaşlık isteği kabul edilemedi"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Karşılıklı istek bulundu, arkadaşlık isteği otomatik kabul edildi"})
+		return
+	}
+
+	// 🔥 Reddedilen istekten sonra bekleme süresi dolmadan tekrar istek gönderilemez
+	var rejectedRequest models.FriendRequest
+	if err := database.DB.Where("sender_id = ? AND receiver_id = ? AND status = ? AND updated_at > ?", userID, input.ReceiverID, models.FriendRequestRejected, time.Now().Add(-friendRequestCooldown)).
+		Order("updated_at DESC").First(&rejectedRequest).Error; err == nil {
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error":       "Bu kullanıcıya tekrar istek göndermek için beklemelisiniz",
+			"retry_after": rejectedRequest.UpdatedAt.Add(friendRequestCooldown),
+		})
+		return
+	}
+
 	friendRequest := models.FriendRequest{
 		SenderID:   userID.(uint),
 		ReceiverID: input.ReceiverID,
-		Status:     "pending",
+		Status:     models.FriendRequestPending,
 	}
 
 	if err := database.DB.Create(&friendRequest).Error; err != nil {
@@ -62,8 +176,10 @@ func GetFriendRequests(c *gin.Context) {
 		return
 	}
 
+	expireFriendRequests(userID.(uint))
+
 	var requests []models.FriendRequest
-	if err := database.DB.Where("receiver_id = ? AND status = 'pending'", userID).Find(&requests).Error; err != nil {
+	if err := database.DB.Where("receiver_id = ? AND status = ?", userID, models.FriendRequestPending).Find(&requests).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Arkadaşlık istekleri alınamadı"})
 		return
 	}
@@ -71,6 +187,61 @@ func GetFriendRequests(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"friend_requests": requests})
 }
 
+// ✅ Gönderilen (bekleyen) arkadaşlık isteklerini getir
+func GetOutgoingFriendRequests(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	expireFriendRequests(userID.(uint))
+
+	var requests []models.FriendRequest
+	if err := database.DB.Where("sender_id = ? AND status = ?", userID, models.FriendRequestPending).Order("id DESC").Find(&requests).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Arkadaşlık istekleri alınamadı"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"friend_requests": requests})
+}
+
+// ✅ Gönderilen arkadaşlık isteğini geri çek
+func CancelFriendRequest(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	expireFriendRequests(userID.(uint))
+
+	requestID := c.Param("request_id")
+	var friendRequest models.FriendRequest
+	if err := database.DB.First(&friendRequest, requestID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Arkadaşlık isteği bulunamadı"})
+		return
+	}
+
+	if friendRequest.SenderID != userID.(uint) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Bu isteği geri çekmeye yetkiniz yok"})
+		return
+	}
+
+	if friendRequest.Status != models.FriendRequestPending {
+		c.JSON(http.StatusConflict, gin.H{"error": "Sadece bekleyen istekler geri çekilebilir"})
+		return
+	}
+
+	friendRequest.Status = models.FriendRequestCancelled
+	if err := database.DB.Save(&friendRequest).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Arkadaşlık isteği geri çekilemedi"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Arkadaşlık isteği geri çekildi"})
+}
+
 // ✅ Arkadaşlık isteğini kabul et
 func AcceptFriendRequest(c *gin.Context) {
 	userID, exists := c.Get("userID")
@@ -79,6 +250,8 @@ func AcceptFriendRequest(c *gin.Context) {
 		return
 	}
 
+	expireFriendRequests(userID.(uint))
+
 	requestID := c.Param("request_id")
 	var friendRequest models.FriendRequest
 	if err := database.DB.First(&friendRequest, requestID).Error; err != nil {
@@ -91,22 +264,27 @@ func AcceptFriendRequest(c *gin.Context) {
 		return
 	}
 
-	friendRequest.Status = "accepted"
-	database.DB.Save(&friendRequest)
+	if isBlockedBetween(friendRequest.SenderID, friendRequest.ReceiverID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Bu isteği kabul edemezsiniz"})
+		return
+	}
 
-	// Arkadaşlık kaydını oluştur
-	friendship := models.Friendship{
-		UserID:   friendRequest.SenderID,
-		FriendID: friendRequest.ReceiverID,
+	if friendRequest.Status != models.FriendRequestPending {
+		c.JSON(http.StatusConflict, gin.H{"error": "Bu istek artık beklemede değil"})
+		return
 	}
-	database.DB.Create(&friendship)
 
-	// Çift yönlü arkadaşlık için ikinci kayıt
-	friendshipReverse := models.Friendship{
-		UserID:   friendRequest.ReceiverID,
-		FriendID: friendRequest.SenderID,
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		return acceptFriendRequest(tx, friendRequest)
+	})
+	if errors.Is(err, errFriendRequestNotPending) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Bu istek artık beklemede değil"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Arkadaşlık isteği kabul edilemedi"})
+		return
 	}
-	database.DB.Create(&friendshipReverse)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Arkadaşlık isteği kabul edildi"})
 }
@@ -119,6 +297,8 @@ func RejectFriendRequest(c *gin.Context) {
 		return
 	}
 
+	expireFriendRequests(userID.(uint))
+
 	requestID := c.Param("request_id")
 	var friendRequest models.FriendRequest
 	if err := database.DB.First(&friendRequest, requestID).Error; err != nil {
@@ -131,7 +311,12 @@ func RejectFriendRequest(c *gin.Context) {
 		return
 	}
 
-	friendRequest.Status = "rejected"
+	if friendRequest.Status != models.FriendRequestPending {
+		c.JSON(http.StatusConflict, gin.H{"error": "Bu istek artık beklemede değil"})
+		return
+	}
+
+	friendRequest.Status = models.FriendRequestRejected
 	database.DB.Save(&friendRequest)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Arkadaşlık isteği reddedildi"})
@@ -145,23 +330,119 @@ func RemoveFriend(c *gin.Context) {
 		return
 	}
 
-	friendID := c.Param("friend_id")
+	friendID, err := strconv.ParseUint(c.Param("friend_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz kullanıcı ID"})
+		return
+	}
 
-	if err := database.DB.Where("user_id = ? AND friend_id = ?", userID, friendID).Delete(&models.Friendship{}).Error; err != nil {
+	var removed int64
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		removed, err = endFriendship(tx, userID.(uint), uint(friendID))
+		return err
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Arkadaş silinemedi"})
 		return
 	}
 
+	if removed == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Bu kullanıcıyla arkadaş değilsiniz"})
+		return
+	}
+
+	// Durumunu sadece arkadaşlarına gösteren taraflar için abonelikler yeniden kontrol edilir
+	revalidatePresenceSubscribers(userID.(uint))
+	revalidatePresenceSubscribers(uint(friendID))
+
 	c.JSON(http.StatusOK, gin.H{"message": "Arkadaşlık sona erdirildi"})
 }
 
+// ✅ Arkadaş listesindeki bir satır
+type friendSummary struct {
+	ID             uint       `json:"id"`
+	Username       string     `json:"username"`
+	FirstName      string     `json:"first_name"`
+	LastName       string     `json:"last_name"`
+	FriendsSince   time.Time  `json:"friends_since"`
+	ConversationID *uint      `json:"conversation_id"`
+	LastMessageAt  *time.Time `json:"last_message_at"`
+}
+
+// 🔥 Arkadaşları Listele (GET /friends?sort=recent|name&page=1&limit=20)
+func GetFriends(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	orderBy := "COALESCE(lm.last_message_at, f.created_at) DESC, u.id ASC"
+	switch c.DefaultQuery("sort", "recent") {
+	case "recent":
+	case "name":
+		orderBy = "LOWER(u.first_name) ASC, LOWER(u.last_name) ASC, u.id ASC"
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Sıralama recent veya name olmalıdır"})
+		return
+	}
+
+	page, limit := getPagination(c)
+	params := map[string]interface{}{
+		"user":   userID.(uint),
+		"limit":  limit,
+		"offset": (page - 1) * limit,
+	}
+
+	// Toplam, silinmiş kullanıcılar hariç tutularak sayfayla aynı şekilde sayılır
+	var total int64
+	database.DB.Table("friendships f").
+		Joins("JOIN users u ON u.id = f.friend_id AND u.deleted_at IS NULL").
+		Where("f.user_id = ? AND f.deleted_at IS NULL", userID).
+		Count(&total)
+
+	var friends []friendSummary
+	err := database.DB.Raw(`
+		SELECT u.id, u.username, u.first_name, u.last_name, f.created_at AS friends_since,
+			dc.conversation_id, lm.last_message_at
+		FROM friendships f
+		JOIN users u ON u.id = f.friend_id AND u.deleted_at IS NULL
+		LEFT JOIN LATERAL (
+			SELECT c.id AS conversation_id FROM conversations c
+			WHERE c.direct_key = LEAST(@user, u.id)::text || ':' || GREATEST(@user, u.id)::text AND c.deleted_at IS NULL
+		) dc ON true
+		LEFT JOIN LATERAL (
+			SELECT MAX(m.created_at) AS last_message_at FROM messages m
+			WHERE m.conversation_id = dc.conversation_id AND m.deleted_at IS NULL
+		) lm ON true
+		WHERE f.user_id = @user AND f.deleted_at IS NULL
+		ORDER BY `+orderBy+`
+		LIMIT @limit OFFSET @offset`, params).Scan(&friends).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Arkadaşlar alınamadı"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"friends": friends,
+		"page":    page,
+		"limit":   limit,
+		"total":   total,
+	})
+}
+
 // ✅ Arkadaşlık route'larını kaydet
 func RegisterFriendRoutes(router *gin.Engine) {
 	friendRoutes := router.Group("/friends")
 	friendRoutes.Use(AuthMiddleware()) // 🔥 JWT Doğrulaması Ekledik
 	{
+		friendRoutes.GET("", GetFriends) // 🔥 Arkadaş listesi
+		friendRoutes.GET("/suggestions", GetFriendSuggestions)
 		friendRoutes.POST("/request", SendFriendRequest)
 		friendRoutes.GET("/requests", GetFriendRequests)
+		friendRoutes.GET("/requests/outgoing", GetOutgoingFriendRequests)
+		friendRoutes.DELETE("/requests/:request_id", CancelFriendRequest)
 		friendRoutes.POST("/accept/:request_id", AcceptFriendRequest)
 		friendRoutes.DELETE("/reject/:request_id", RejectFriendRequest)
 		friendRoutes.DELETE("/:friend_id", RemoveFriend)
@@ -2,11 +2,13 @@ package routes
 
 import (
 	"net/http"
+	"time"
 
 	"arcurachat_api/database"
 	"arcurachat_api/models"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // ✅ Grup oluşturma
@@ -18,7 +20,11 @@ func CreateGroup(c *gin.Context) {
 	}
 
 	var input struct {
-		Name string `json:"name" binding:"required"`
+		Name        string `json:"name" binding:"required"`
+		Type        string `json:"type"` // group (varsayılan) veya channel
+		Description string `json:"description"`
+		AvatarURL   string `json:"avatar_url"`
+		Topic       string `json:"topic"`
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -26,8 +32,37 @@ func CreateGroup(c *gin.Context) {
 		return
 	}
 
-	group := models.Group{Name: input.Name, OwnerID: userID.(uint)}
-	if err := database.DB.Create(&group).Error; err != nil {
+	if input.Type == "" {
+		input.Type = models.GroupTypeGroup
+	}
+	if input.Type != models.GroupTypeGroup && input.Type != models.GroupTypeChannel {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Grup tipi group veya channel olmalıdır"})
+		return
+	}
+
+	group := models.Group{
+		Name:        input.Name,
+		Type:        input.Type,
+		Description: input.Description,
+		AvatarURL:   input.AvatarURL,
+		Topic:       input.Topic,
+		OwnerID:     userID.(uint),
+	}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&group).Error; err != nil {
+			return err
+		}
+
+		// 🔥 Grup sahibi de grubun üyesidir
+		owner := models.GroupMember{GroupID: group.ID, UserID: group.OwnerID, Role: models.GroupRoleOwner}
+		if err := tx.Create(&owner).Error; err != nil {
+			return err
+		}
+
+		// 🔥 Grubun konuşmasını oluştur
+		return ensureGroupConversation(tx, &group)
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Grup oluşturulamadı"})
 		return
 	}
@@ -35,16 +70,144 @@ func CreateGroup(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Grup başarıyla oluşturuldu", "data": group})
 }
 
+// ✅ Grup listesinde son mesaj önizlemesi
+type lastMessagePreview struct {
+	ID        uint      `json:"id"`
+	SenderID  uint      `json:"sender_id"`
+	Type      string    `json:"type"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ✅ Kullanıcının grup listesindeki bir satır
+type groupSummary struct {
+	ID             uint                `json:"id"`
+	Name           string              `json:"name"`
+	Type           string              `json:"type"`
+	OwnerID        uint                `json:"owner_id"`
+	ConversationID uint                `json:"conversation_id"`
+	Role           string              `json:"role"`
+	MemberCount    int64               `json:"member_count"`
+	UnreadCount    int64               `json:"unread_count"`
+	LastMessage    *lastMessagePreview `gorm:"-" json:"last_message"`
+
+	LastMessageID        *uint      `json:"-"`
+	LastMessageSenderID  uint       `json:"-"`
+	LastMessageType      string     `json:"-"`
+	LastMessageContent   string     `json:"-"`
+	LastMessageCreatedAt *time.Time `json:"-"`
+}
+
+// ✅ Önizleme için mesaj içeriğini kısalt
+func previewContent(content string) string {
+	const maxPreviewLength = 100
+
+	runes := []rune(content)
+	if len(runes) <= maxPreviewLength {
+		return content
+	}
+	return string(runes[:maxPreviewLength]) + "…"
+}
+
+// 🔥 Kullanıcının Gruplarını Listele (GET /groups)
+// Üye sayısı, son mesaj ve okunmamış sayısı tek sorguda hesaplanır.
+// Son mesaj ve okunmamışlar, üyenin katıldığı andan sonraki ve kendisinden gizlenmemiş mesajlardan hesaplanır.
+func GetMyGroups(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	page, limit := getPagination(c)
+	params := map[string]interface{}{
+		"user":   userID.(uint),
+		"limit":  limit,
+		"offset": (page - 1) * limit,
+	}
+
+	const membershipJoin = `
+		FROM groups g
+		LEFT JOIN group_members gm ON gm.group_id = g.id AND gm.user_id = @user AND gm.deleted_at IS NULL`
+	const membershipWhere = `
+		WHERE g.deleted_at IS NULL AND (gm.id IS NOT NULL OR g.owner_id = @user)`
+
+	var total int64
+	if err := database.DB.Raw("SELECT COUNT(*)"+membershipJoin+membershipWhere, params).Scan(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gruplar alınamadı"})
+		return
+	}
+
+	var groups []groupSummary
+	err := database.DB.Raw(`
+		SELECT g.id, g.name, g.type, g.owner_id, g.conversation_id,
+			CASE WHEN g.owner_id = @user THEN 'owner' ELSE COALESCE(gm.role, 'member') END AS role,
+			(SELECT COUNT(*) FROM group_members m WHERE m.group_id = g.id AND m.deleted_at IS NULL) AS member_count,
+			(SELECT COUNT(*) FROM messages um
+				WHERE um.conversation_id = g.conversation_id AND um.deleted_at IS NULL AND um.thread_root_id IS NULL
+				AND um.deleted_for_everyone_at IS NULL AND um.created_at >= cp.joined_at
+				AND um.sender_id <> @user AND um.id > COALESCE(cp.last_read_message_id, 0)
+				AND NOT EXISTS (
+					SELECT 1 FROM message_hides mh WHERE mh.message_id = um.id AND mh.user_id = @user AND mh.deleted_at IS NULL
+				)) AS unread_count,
+			lm.id AS last_message_id, lm.sender_id AS last_message_sender_id, lm.type AS last_message_type,
+			lm.content AS last_message_content, lm.created_at AS last_message_created_at`+membershipJoin+`
+		LEFT JOIN conversation_participants cp ON cp.conversation_id = g.conversation_id
+			AND cp.user_id = @user AND cp.left_at IS NULL AND cp.deleted_at IS NULL
+		LEFT JOIN LATERAL (
+			SELECT lmm.id, lmm.sender_id, lmm.type, lmm.content, lmm.created_at FROM messages lmm
+			WHERE lmm.conversation_id = g.conversation_id AND lmm.deleted_at IS NULL AND lmm.thread_root_id IS NULL
+			AND lmm.created_at >= cp.joined_at
+			AND NOT EXISTS (
+				SELECT 1 FROM message_hides mh WHERE mh.message_id = lmm.id AND mh.user_id = @user AND mh.deleted_at IS NULL
+			)
+			ORDER BY lmm.id DESC LIMIT 1
+		) lm ON true`+membershipWhere+`
+		ORDER BY COALESCE(lm.created_at, g.created_at) DESC, g.id DESC
+		LIMIT @limit OFFSET @offset`, params).Scan(&groups).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gruplar alınamadı"})
+		return
+	}
+
+	for i := range groups {
+		if groups[i].LastMessageID == nil {
+			continue
+		}
+		groups[i].LastMessage = &lastMessagePreview{
+			ID:        *groups[i].LastMessageID,
+			SenderID:  groups[i].LastMessageSenderID,
+			Type:      groups[i].LastMessageType,
+			Content:   previewContent(groups[i].LastMessageContent),
+			CreatedAt: *groups[i].LastMessageCreatedAt,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"groups": groups,
+		"page":   page,
+		"limit":  limit,
+		"total":  total,
+	})
+}
+
 // ✅ Grup bilgilerini getir
 func GetGroup(c *gin.Context) {
 	groupID := c.Param("group_id")
 
 	var group models.Group
-	if err := database.DB.Preload("Members").First(&group, groupID).Error; err != nil {
+	if err := database.DB.First(&group, groupID).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Grup bulunamadı"})
 		return
 	}
 
+	database.DB.Model(&models.GroupMember{}).Where("group_id = ?", group.ID).Count(&group.MemberCount)
+
+	// 🔥 Kanallar on binlerce aboneye ulaşabilir, aboneler GET /groups/:group_id/members ile sayfalanır
+	if group.Type != models.GroupTypeChannel {
+		database.DB.Where("group_id = ?", group.ID).Find(&group.Members)
+	}
+
 	c.JSON(http.StatusOK, group)
 }
 
@@ -64,13 +227,24 @@ func UpdateGroup(c *gin.Context) {
 		return
 	}
 
-	if group.OwnerID != userID.(uint) {
+	role := groupRole(group, userID.(uint))
+	if role == "" {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Bu grubu güncellemeye yetkiniz yok"})
 		return
 	}
 
 	var input struct {
-		Name string `json:"name"`
+		Name        string  `json:"name"`
+		Description *string `json:"description"`
+		AvatarURL   *string `json:"avatar_url"`
+		Topic       *string `json:"topic"`
+		Settings    *struct {
+			OnlyAdminsCanPost     *bool `json:"only_admins_can_post"`
+			OnlyAdminsCanEditInfo *bool `json:"only_admins_can_edit_info"`
+			MembersCanInvite      *bool `json:"members_can_invite"`
+			SlowModeSeconds       *int  `json:"slow_mode_seconds"`
+			MembersCanPin         *bool `json:"members_can_pin"`
+		} `json:"settings"`
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -78,7 +252,81 @@ func UpdateGroup(c *gin.Context) {
 		return
 	}
 
-	database.DB.Model(&group).Updates(models.Group{Name: input.Name})
+	updates := map[string]interface{}{}
+	if input.Name != "" {
+		updates["name"] = input.Name
+	}
+	if input.Description != nil {
+		updates["description"] = *input.Description
+	}
+	if input.AvatarURL != nil {
+		updates["avatar_url"] = *input.AvatarURL
+	}
+	if input.Topic != nil {
+		updates["topic"] = *input.Topic
+	}
+
+	// 🔥 Grup bilgileri ayarlara göre sadece yöneticiler tarafından düzenlenebilir
+	if len(updates) > 0 && !canEditGroupInfo(group, role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Bu grubun bilgilerini sadece yöneticiler düzenleyebilir"})
+		return
+	}
+
+	// 🔥 Ayarları sadece yöneticiler değiştirebilir
+	if input.Settings != nil {
+		if !isGroupAdmin(role) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Grup ayarlarını sadece yöneticiler değiştirebilir"})
+			return
+		}
+
+		if input.Settings.OnlyAdminsCanPost != nil {
+			updates["setting_only_admins_can_post"] = *input.Settings.OnlyAdminsCanPost
+		}
+		if input.Settings.OnlyAdminsCanEditInfo != nil {
+			updates["setting_only_admins_can_edit_info"] = *input.Settings.OnlyAdminsCanEditInfo
+		}
+		if input.Settings.MembersCanInvite != nil {
+			updates["setting_members_can_invite"] = *input.Settings.MembersCanInvite
+		}
+		if input.Settings.SlowModeSeconds != nil {
+			if *input.Settings.SlowModeSeconds < 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Yavaş mod süresi negatif olamaz"})
+				return
+			}
+			updates["setting_slow_mode_seconds"] = *input.Settings.SlowModeSeconds
+		}
+		if input.Settings.MembersCanPin != nil {
+			updates["setting_members_can_pin"] = *input.Settings.MembersCanPin
+		}
+	}
+
+	if len(updates) == 0 {
+		c.JSON(http.StatusOK, gin.H{"message": "Grup bilgileri güncellendi", "data": group})
+		return
+	}
+
+	oldName := group.Name
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&group).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		// 🔥 İsim değiştiyse konuşmaya sistem mesajı yaz
+		if input.Name == "" || input.Name == oldName {
+			return nil
+		}
+		return createSystemMessage(tx, group.ConversationID, models.SystemEventGroupRenamed, models.JSONMap{
+			"actor_id": userID.(uint),
+			"old_name": oldName,
+			"new_name": input.Name,
+		})
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Grup bilgileri güncellenemedi"})
+		return
+	}
+
+	database.DB.First(&group, group.ID)
 	c.JSON(http.StatusOK, gin.H{"message": "Grup bilgileri güncellendi", "data": group})
 }
 
@@ -103,7 +351,15 @@ func DeleteGroup(c *gin.Context) {
 		return
 	}
 
-	if err := database.DB.Delete(&group).Error; err != nil {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if group.ConversationID != 0 {
+			if err := tx.Delete(&models.Conversation{}, group.ConversationID).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Delete(&group).Error
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Grup silinemedi"})
 		return
 	}
@@ -127,8 +383,8 @@ func AddMemberToGroup(c *gin.Context) {
 		return
 	}
 
-	// Kullanıcı grup sahibi mi?
-	if group.OwnerID != userID.(uint) {
+	// 🔥 Kullanıcı yönetici mi, ya da üyelerin davet etmesine izin var mı?
+	if !canInviteToGroup(group, groupRole(group, userID.(uint))) {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Bu gruba üye eklemeye yetkiniz yok"})
 		return
 	}
@@ -150,6 +406,24 @@ func AddMemberToGroup(c *gin.Context) {
 		return
 	}
 
+	// 🔥 Engellenen kullanıcıyı gruba ekleyemez, engelleyen kullanıcı tarafından eklenemez
+	if isBlockedBetween(userID.(uint), input.UserID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Bu kullanıcıyı gruba ekleyemezsiniz"})
+		return
+	}
+
+	// 🔥 Kullanıcının gizlilik ayarı kimlerin onu gruba ekleyebileceğini belirler
+	if !privacyAllows(getPrivacySettings(input.UserID).GroupAdds, input.UserID, userID.(uint)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Bu kullanıcı gizlilik ayarları nedeniyle gruba eklenemez"})
+		return
+	}
+
+	// 🔥 Yasaklı kullanıcı tekrar eklenemez
+	if isBannedFromGroup(group.ID, input.UserID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Kullanıcı bu gruptan yasaklı"})
+		return
+	}
+
 	// Kullanıcı zaten grupta mı?
 	var existingMember models.GroupMember
 	if err := database.DB.Where("group_id = ? AND user_id = ?", group.ID, input.UserID).First(&existingMember).Error; err == nil {
@@ -161,9 +435,31 @@ func AddMemberToGroup(c *gin.Context) {
 	groupMember := models.GroupMember{
 		GroupID: group.ID,
 		UserID:  input.UserID,
+		Role:    models.GroupRoleMember,
 	}
 
-	if err := database.DB.Create(&groupMember).Error; err != nil {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&groupMember).Error; err != nil {
+			return err
+		}
+
+		// 🔥 Kullanıcıyı grup konuşmasına da katılımcı olarak ekle
+		if err := ensureGroupConversation(tx, &group); err != nil {
+			return err
+		}
+		if err := addParticipant(tx, group.ConversationID, input.UserID); err != nil {
+			return err
+		}
+
+		if group.Type == models.GroupTypeChannel {
+			return nil
+		}
+		return createSystemMessage(tx, group.ConversationID, models.SystemEventMemberAdded, models.JSONMap{
+			"actor_id": userID.(uint),
+			"user_id":  input.UserID,
+		})
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Kullanıcı gruba eklenemedi"})
 		return
 	}
@@ -172,6 +468,165 @@ func AddMemberToGroup(c *gin.Context) {
 }
 
 
+// ✅ Grup üyelerini / kanal abonelerini sayfalı getir (GET /groups/:group_id/members)
+func GetGroupMembers(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	groupID := c.Param("group_id")
+
+	var group models.Group
+	if err := database.DB.First(&group, groupID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Grup bulunamadı"})
+		return
+	}
+
+	// 🔥 Kanal abone listesini sadece yöneticiler görebilir
+	role := groupRole(group, userID.(uint))
+	if role == "" || (group.Type == models.GroupTypeChannel && !isGroupAdmin(role)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Üyeleri görüntülemeye yetkiniz yok"})
+		return
+	}
+
+	page, limit := getPagination(c)
+
+	var total int64
+	database.DB.Model(&models.GroupMember{}).Where("group_id = ?", group.ID).Count(&total)
+
+	var members []models.GroupMember
+	if err := database.DB.Where("group_id = ?", group.ID).Order("id ASC").
+		Limit(limit).Offset((page - 1) * limit).Find(&members).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Üyeler alınamadı"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"members": members, "page": page, "limit": limit, "total": total})
+}
+
+// 🔥 Kanala abone ol (POST /groups/:group_id/subscribe)
+func SubscribeToChannel(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	groupID := c.Param("group_id")
+
+	var group models.Group
+	if err := database.DB.First(&group, groupID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Grup bulunamadı"})
+		return
+	}
+
+	if group.Type != models.GroupTypeChannel {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Sadece kanallara abone olunabilir"})
+		return
+	}
+
+	if isBannedFromGroup(group.ID, userID.(uint)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Bu kanaldan yasaklısınız"})
+		return
+	}
+
+	var existingMember models.GroupMember
+	if err := database.DB.Where("group_id = ? AND user_id = ?", group.ID, userID).First(&existingMember).Error; err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Bu kanala zaten abonesiniz"})
+		return
+	}
+
+	subscriber := models.GroupMember{
+		GroupID: group.ID,
+		UserID:  userID.(uint),
+		Role:    models.GroupRoleMember,
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&subscriber).Error; err != nil {
+			return err
+		}
+		if err := ensureGroupConversation(tx, &group); err != nil {
+			return err
+		}
+		return addParticipant(tx, group.ConversationID, subscriber.UserID)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Kanala abone olunamadı"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Kanala abone olundu"})
+}
+
+// ✅ Kanal aboneliğini bırak (DELETE /groups/:group_id/subscribe)
+func UnsubscribeFromChannel(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	groupID := c.Param("group_id")
+
+	var group models.Group
+	if err := database.DB.First(&group, groupID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Grup bulunamadı"})
+		return
+	}
+
+	if group.Type != models.GroupTypeChannel {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Sadece kanal aboneliği bırakılabilir"})
+		return
+	}
+
+	if group.OwnerID == userID.(uint) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Kanal sahibi aboneliği bırakamaz"})
+		return
+	}
+
+	var member models.GroupMember
+	if err := database.DB.Where("group_id = ? AND user_id = ?", group.ID, userID).First(&member).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Bu kanala abone değilsiniz"})
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		return removeGroupMember(tx, group, member, userID.(uint))
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Abonelik bırakılamadı"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Kanal aboneliği bırakıldı"})
+}
+
+// 🔥 Üyeyi gruptan ve grup konuşmasından çıkar
+// Yeni mesajlara erişim kapanır, çıkarılana kadarki geçmiş korunur.
+func removeGroupMember(tx *gorm.DB, group models.Group, member models.GroupMember, actorID uint) error {
+	if err := tx.Delete(&member).Error; err != nil {
+		return err
+	}
+
+	if group.ConversationID == 0 {
+		return nil
+	}
+
+	// 🔥 Kanallarda abone hareketleri sohbete yazılmaz
+	if group.Type != models.GroupTypeChannel {
+		if err := createSystemMessage(tx, group.ConversationID, models.SystemEventMemberRemoved, models.JSONMap{
+			"actor_id": actorID,
+			"user_id":  member.UserID,
+		}); err != nil {
+			return err
+		}
+	}
+	return removeParticipant(tx, group.ConversationID, member.UserID)
+}
+
 // ✅ Gruptan kullanıcı çıkar
 func RemoveMemberFromGroup(c *gin.Context) {
 	userID, exists := c.Get("userID")
@@ -189,12 +644,41 @@ func RemoveMemberFromGroup(c *gin.Context) {
 		return
 	}
 
-	if group.OwnerID != userID.(uint) {
+	role := groupRole(group, userID.(uint))
+	if !isGroupAdmin(role) {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Bu gruptan kullanıcı çıkarmaya yetkiniz yok"})
 		return
 	}
 
-	if err := database.DB.Where("group_id = ? AND user_id = ?", groupID, removeUserID).Delete(&models.GroupMember{}).Error; err != nil {
+	var member models.GroupMember
+	if err := database.DB.Where("group_id = ? AND user_id = ?", group.ID, removeUserID).First(&member).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Kullanıcı grupta değil"})
+		return
+	}
+
+	if member.UserID == group.OwnerID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Grup sahibi gruptan çıkarılamaz"})
+		return
+	}
+
+	// 🔥 Yöneticileri sadece grup sahibi çıkarabilir
+	if !canModerateUser(group, role, member.UserID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Yöneticileri sadece grup sahibi çıkarabilir"})
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := removeGroupMember(tx, group, member, userID.(uint)); err != nil {
+			return err
+		}
+		return logModeration(tx, models.ModerationLog{
+			GroupID:      group.ID,
+			ActorID:      userID.(uint),
+			TargetUserID: member.UserID,
+			Action:       models.ModerationActionRemove,
+		})
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Kullanıcı gruptan çıkarılamadı"})
 		return
 	}
@@ -202,16 +686,82 @@ func RemoveMemberFromGroup(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Kullanıcı gruptan çıkarıldı"})
 }
 
+// ✅ Üyenin rolünü değiştir (sadece grup sahibi)
+func UpdateMemberRole(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	groupID := c.Param("group_id")
+	memberUserID := c.Param("user_id")
+
+	var group models.Group
+	if err := database.DB.First(&group, groupID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Grup bulunamadı"})
+		return
+	}
+
+	if group.OwnerID != userID.(uint) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Üye rollerini sadece grup sahibi değiştirebilir"})
+		return
+	}
+
+	var input struct {
+		Role string `json:"role" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz veri"})
+		return
+	}
+
+	if input.Role != models.GroupRoleAdmin && input.Role != models.GroupRoleMember {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Rol admin veya member olmalıdır"})
+		return
+	}
+
+	var member models.GroupMember
+	if err := database.DB.Where("group_id = ? AND user_id = ?", group.ID, memberUserID).First(&member).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Kullanıcı grupta değil"})
+		return
+	}
+
+	if member.UserID == group.OwnerID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Grup sahibinin rolü değiştirilemez"})
+		return
+	}
+
+	if err := database.DB.Model(&member).Update("role", input.Role).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Rol güncellenemedi"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Üye rolü güncellendi", "data": member})
+}
+
 // ✅ Grup route'larını kaydet
 func RegisterGroupRoutes(router *gin.Engine) {
 	groupRoutes := router.Group("/groups")
 	groupRoutes.Use(AuthMiddleware()) // 🔥 JWT Doğrulaması Ekledik
 	{
+		groupRoutes.GET("", GetMyGroups) // 🔥 Kullanıcının grupları
 		groupRoutes.POST("/create", CreateGroup)
 		groupRoutes.GET("/:group_id", GetGroup)
 		groupRoutes.PUT("/:group_id", UpdateGroup)
 		groupRoutes.DELETE("/:group_id", DeleteGroup)
+		groupRoutes.GET("/:group_id/members", GetGroupMembers)
 		groupRoutes.POST("/:group_id/members", AddMemberToGroup)
 		groupRoutes.DELETE("/:group_id/members/:user_id", RemoveMemberFromGroup)
+		groupRoutes.PUT("/:group_id/members/:user_id/role", UpdateMemberRole)
+		groupRoutes.GET("/:group_id/bans", GetGroupBans)
+		groupRoutes.POST("/:group_id/bans", BanGroupMember)
+		groupRoutes.DELETE("/:group_id/bans/:user_id", UnbanGroupMember)
+		groupRoutes.POST("/:group_id/mutes", MuteGroupMember)
+		groupRoutes.DELETE("/:group_id/mutes/:user_id", UnmuteGroupMember)
+		groupRoutes.GET("/:group_id/moderation-log", GetModerationLog)
+		groupRoutes.POST("/:group_id/subscribe", SubscribeToChannel)
+		groupRoutes.DELETE("/:group_id/subscribe", UnsubscribeFromChannel)
 	}
 }
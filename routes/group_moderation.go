@@ -0,0 +1,322 @@
+package routes
+
+import (
+	"net/http"
+	"time"
+
+	"arcurachat_api/database"
+	"arcurachat_api/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ✅ Kullanıcının grupta aktif bir yasağı var mı?
+func isBannedFromGroup(groupID, userID uint) bool {
+	var count int64
+	database.DB.Model(&models.GroupBan{}).
+		Where("group_id = ? AND user_id = ? AND (expires_at IS NULL OR expires_at > ?)", groupID, userID, time.Now()).
+		Count(&count)
+	return count > 0
+}
+
+// ✅ Moderasyon kaydı yaz
+func logModeration(tx *gorm.DB, entry models.ModerationLog) error {
+	return tx.Create(&entry).Error
+}
+
+// 🔥 Kullanıcıyı gruptan yasakla (POST /groups/:group_id/bans)
+// Kullanıcı grupta ise çıkarılır; yasak süresince tekrar eklenemez.
+func BanGroupMember(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	groupID := c.Param("group_id")
+
+	var group models.Group
+	if err := database.DB.First(&group, groupID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Grup bulunamadı"})
+		return
+	}
+
+	var input struct {
+		UserID          uint   `json:"user_id" binding:"required"`
+		Reason          string `json:"reason"`
+		DurationMinutes int    `json:"duration_minutes"` // 0 ise süresiz
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil || input.DurationMinutes < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz veri"})
+		return
+	}
+
+	if !canModerateUser(group, groupRole(group, userID.(uint)), input.UserID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Bu kullanıcıyı yasaklamaya yetkiniz yok"})
+		return
+	}
+
+	if isBannedFromGroup(group.ID, input.UserID) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Kullanıcı zaten yasaklı"})
+		return
+	}
+
+	ban := models.GroupBan{
+		GroupID:  group.ID,
+		UserID:   input.UserID,
+		BannedBy: userID.(uint),
+		Reason:   input.Reason,
+	}
+	if input.DurationMinutes > 0 {
+		expiresAt := time.Now().Add(time.Duration(input.DurationMinutes) * time.Minute)
+		ban.ExpiresAt = &expiresAt
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&ban).Error; err != nil {
+			return err
+		}
+
+		var member models.GroupMember
+		if err := tx.Where("group_id = ? AND user_id = ?", group.ID, input.UserID).First(&member).Error; err == nil {
+			if err := removeGroupMember(tx, group, member, userID.(uint)); err != nil {
+				return err
+			}
+		}
+
+		return logModeration(tx, models.ModerationLog{
+			GroupID:      group.ID,
+			ActorID:      userID.(uint),
+			TargetUserID: input.UserID,
+			Action:       models.ModerationActionBan,
+			Reason:       input.Reason,
+			ExpiresAt:    ban.ExpiresAt,
+		})
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Kullanıcı yasaklanamadı"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Kullanıcı gruptan yasaklandı", "data": ban})
+}
+
+// ✅ Yasağı kaldır (DELETE /groups/:group_id/bans/:user_id)
+func UnbanGroupMember(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	groupID := c.Param("group_id")
+	bannedUserID := c.Param("user_id")
+
+	var group models.Group
+	if err := database.DB.First(&group, groupID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Grup bulunamadı"})
+		return
+	}
+
+	if !isGroupAdmin(groupRole(group, userID.(uint))) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Yasak kaldırmaya yetkiniz yok"})
+		return
+	}
+
+	var bans []models.GroupBan
+	if err := database.DB.Where("group_id = ? AND user_id = ? AND (expires_at IS NULL OR expires_at > ?)", group.ID, bannedUserID, time.Now()).Find(&bans).Error; err != nil || len(bans) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Aktif yasak bulunamadı"})
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&bans).Error; err != nil {
+			return err
+		}
+		return logModeration(tx, models.ModerationLog{
+			GroupID:      group.ID,
+			ActorID:      userID.(uint),
+			TargetUserID: bans[0].UserID,
+			Action:       models.ModerationActionUnban,
+		})
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Yasak kaldırılamadı"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Yasak kaldırıldı"})
+}
+
+// ✅ Aktif yasakları listele (GET /groups/:group_id/bans)
+func GetGroupBans(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	groupID := c.Param("group_id")
+
+	var group models.Group
+	if err := database.DB.First(&group, groupID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Grup bulunamadı"})
+		return
+	}
+
+	if !isGroupAdmin(groupRole(group, userID.(uint))) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Yasakları görüntülemeye yetkiniz yok"})
+		return
+	}
+
+	var bans []models.GroupBan
+	if err := database.DB.Where("group_id = ? AND (expires_at IS NULL OR expires_at > ?)", group.ID, time.Now()).Order("id DESC").Find(&bans).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Yasaklar alınamadı"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"bans": bans})
+}
+
+// 🔥 Üyeyi süreli sustur (POST /groups/:group_id/mutes)
+func MuteGroupMember(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	groupID := c.Param("group_id")
+
+	var group models.Group
+	if err := database.DB.First(&group, groupID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Grup bulunamadı"})
+		return
+	}
+
+	var input struct {
+		UserID          uint   `json:"user_id" binding:"required"`
+		Reason          string `json:"reason"`
+		DurationMinutes int    `json:"duration_minutes" binding:"required,min=1"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz veri"})
+		return
+	}
+
+	if !canModerateUser(group, groupRole(group, userID.(uint)), input.UserID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Bu kullanıcıyı susturmaya yetkiniz yok"})
+		return
+	}
+
+	mute := models.GroupMute{
+		GroupID:   group.ID,
+		UserID:    input.UserID,
+		MutedBy:   userID.(uint),
+		Reason:    input.Reason,
+		ExpiresAt: time.Now().Add(time.Duration(input.DurationMinutes) * time.Minute),
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&mute).Error; err != nil {
+			return err
+		}
+		return logModeration(tx, models.ModerationLog{
+			GroupID:      group.ID,
+			ActorID:      userID.(uint),
+			TargetUserID: input.UserID,
+			Action:       models.ModerationActionMute,
+			Reason:       input.Reason,
+			ExpiresAt:    &mute.ExpiresAt,
+		})
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Kullanıcı susturulamadı"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Kullanıcı susturuldu", "data": mute})
+}
+
+// ✅ Susturmayı kaldır (DELETE /groups/:group_id/mutes/:user_id)
+func UnmuteGroupMember(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	groupID := c.Param("group_id")
+	mutedUserID := c.Param("user_id")
+
+	var group models.Group
+	if err := database.DB.First(&group, groupID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Grup bulunamadı"})
+		return
+	}
+
+	if !isGroupAdmin(groupRole(group, userID.(uint))) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Susturma kaldırmaya yetkiniz yok"})
+		return
+	}
+
+	var mutes []models.GroupMute
+	if err := database.DB.Where("group_id = ? AND user_id = ? AND expires_at > ?", group.ID, mutedUserID, time.Now()).Find(&mutes).Error; err != nil || len(mutes) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Aktif susturma bulunamadı"})
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&mutes).Error; err != nil {
+			return err
+		}
+		return logModeration(tx, models.ModerationLog{
+			GroupID:      group.ID,
+			ActorID:      userID.(uint),
+			TargetUserID: mutes[0].UserID,
+			Action:       models.ModerationActionUnmute,
+		})
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Susturma kaldırılamadı"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Susturma kaldırıldı"})
+}
+
+// ✅ Moderasyon kayıtlarını getir (GET /groups/:group_id/moderation-log)
+func GetModerationLog(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	groupID := c.Param("group_id")
+
+	var group models.Group
+	if err := database.DB.First(&group, groupID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Grup bulunamadı"})
+		return
+	}
+
+	if !isGroupAdmin(groupRole(group, userID.(uint))) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Moderasyon kayıtlarını görüntülemeye yetkiniz yok"})
+		return
+	}
+
+	page, limit := getPagination(c)
+
+	var entries []models.ModerationLog
+	if err := database.DB.Where("group_id = ?", group.ID).Order("id DESC").
+		Limit(limit).Offset((page - 1) * limit).Find(&entries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Moderasyon kayıtları alınamadı"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"entries": entries, "page": page, "limit": limit})
+}
@@ -0,0 +1,121 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"arcurachat_api/database"
+	"arcurachat_api/models"
+)
+
+// ✅ Kullanıcının gruptaki rolü (üye değilse boş döner)
+func groupRole(group models.Group, userID uint) string {
+	if group.OwnerID == userID {
+		return models.GroupRoleOwner
+	}
+
+	var member models.GroupMember
+	if err := database.DB.Where("group_id = ? AND user_id = ?", group.ID, userID).First(&member).Error; err != nil {
+		return ""
+	}
+	if member.Role == "" {
+		return models.GroupRoleMember
+	}
+	return member.Role
+}
+
+// ✅ Rol yönetici yetkisine sahip mi? (sahip de yöneticidir)
+func isGroupAdmin(role string) bool {
+	return role == models.GroupRoleOwner || role == models.GroupRoleAdmin
+}
+
+// ✅ Hedef kullanıcı üzerinde moderasyon yapabilir mi?
+// Sahip moderasyona tabi değildir, yöneticileri sadece sahip yönetebilir.
+func canModerateUser(group models.Group, role string, targetUserID uint) bool {
+	if !isGroupAdmin(role) || targetUserID == group.OwnerID {
+		return false
+	}
+	if role == models.GroupRoleOwner {
+		return true
+	}
+	return groupRole(group, targetUserID) != models.GroupRoleAdmin
+}
+
+// ✅ Grup bilgilerini düzenleyebilir mi?
+func canEditGroupInfo(group models.Group, role string) bool {
+	if role == "" {
+		return false
+	}
+	return isGroupAdmin(role) || !group.Settings.OnlyAdminsCanEditInfo
+}
+
+// ✅ Gruba kullanıcı ekleyebilir mi?
+func canInviteToGroup(group models.Group, role string) bool {
+	if role == "" {
+		return false
+	}
+	return isGroupAdmin(role) || group.Settings.MembersCanInvite
+}
+
+// ✅ Grupta mesaj sabitleyebilir mi? (kanallarda sadece yöneticiler)
+func canPinInGroup(group models.Group, role string) bool {
+	if role == "" {
+		return false
+	}
+	return isGroupAdmin(role) || (group.Type != models.GroupTypeChannel && group.Settings.MembersCanPin)
+}
+
+// ✅ Konuşma bir gruba aitse grubu getir
+func groupForConversation(conversationID uint) (models.Group, bool) {
+	var group models.Group
+	if err := database.DB.Where("conversation_id = ?", conversationID).First(&group).Error; err != nil {
+		return group, false
+	}
+	return group, true
+}
+
+// 🔥 Grup ayarlarına göre mesaj gönderme kısıtlamalarını kontrol et
+// Kısıtlama yoksa 0 ve boş mesaj döner.
+func groupPostRestriction(conversationID, userID uint) (int, string) {
+	group, ok := groupForConversation(conversationID)
+	if !ok {
+		return 0, ""
+	}
+
+	// 🔥 Süreli susturulan üye mesaj gönderemez
+	var mute models.GroupMute
+	if err := database.DB.Where("group_id = ? AND user_id = ? AND expires_at > ?", group.ID, userID, time.Now()).
+		Order("expires_at DESC").First(&mute).Error; err == nil {
+		return http.StatusForbidden, fmt.Sprintf("Bu grupta %s tarihine kadar susturuldunuz", mute.ExpiresAt.Format(time.RFC3339))
+	}
+
+	role := groupRole(group, userID)
+	if isGroupAdmin(role) {
+		return 0, ""
+	}
+
+	// 🔥 Kanallarda sadece yöneticiler paylaşım yapar
+	if group.Type == models.GroupTypeChannel {
+		return http.StatusForbidden, "Bu kanalda sadece yöneticiler paylaşım yapabilir"
+	}
+
+	if group.Settings.OnlyAdminsCanPost {
+		return http.StatusForbidden, "Bu grupta sadece yöneticiler mesaj gönderebilir"
+	}
+
+	// 🔥 Yavaş mod: üyenin son mesajından bu yana yeterli süre geçti mi?
+	if group.Settings.SlowModeSeconds > 0 {
+		var last models.Message
+		err := database.DB.Where("conversation_id = ? AND sender_id = ? AND type = ?", conversationID, userID, models.MessageTypeUser).
+			Order("id DESC").First(&last).Error
+		if err == nil {
+			wait := time.Duration(group.Settings.SlowModeSeconds)*time.Second - time.Since(last.CreatedAt)
+			if wait > 0 {
+				return http.StatusTooManyRequests, fmt.Sprintf("Yavaş mod açık, %d saniye sonra tekrar deneyin", int(wait.Seconds())+1)
+			}
+		}
+	}
+
+	return 0, ""
+}
@@ -0,0 +1,96 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"arcurachat_api/database"
+	"arcurachat_api/models"
+
+	"gorm.io/gorm"
+)
+
+// Sonradan katılan üye, katılmadan önceki mesajları okunmamış veya son mesaj olarak görmemeli
+func TestGetMyGroupsMemberJoinedAfterHistory(t *testing.T) {
+	setupTestDB(t)
+
+	owner := createTestUser(t, "owner")
+	member := createTestUser(t, "member")
+
+	group := models.Group{Name: "Tarihçe", Type: models.GroupTypeGroup, OwnerID: owner.ID}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&group).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&models.GroupMember{GroupID: group.ID, UserID: owner.ID, Role: models.GroupRoleOwner}).Error; err != nil {
+			return err
+		}
+		return ensureGroupConversation(tx, &group)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	past := time.Now().Add(-time.Hour)
+	createTestMessage(t, group.ConversationID, owner.ID, "katılımdan önce 1", past)
+	createTestMessage(t, group.ConversationID, owner.ID, "katılımdan önce 2", past.Add(time.Minute))
+
+	if err := database.DB.Create(&models.GroupMember{GroupID: group.ID, UserID: member.ID, Role: models.GroupRoleMember}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := addParticipant(database.DB, group.ConversationID, member.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	fetch := func() groupSummary {
+		t.Helper()
+		recorder := performRequest(t, http.MethodGet, "/groups", "/groups", member.ID, nil, GetMyGroups)
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("GET /groups = %d: %s", recorder.Code, recorder.Body.String())
+		}
+		var response struct {
+			Groups []groupSummary `json:"groups"`
+		}
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatal(err)
+		}
+		if len(response.Groups) != 1 {
+			t.Fatalf("got %d groups, want 1", len(response.Groups))
+		}
+		return response.Groups[0]
+	}
+
+	summary := fetch()
+	if summary.UnreadCount != 0 {
+		t.Errorf("unread_count = %d, want 0 for history before joining", summary.UnreadCount)
+	}
+	if summary.LastMessage != nil {
+		t.Errorf("last_message = %q, want none before joining", summary.LastMessage.Content)
+	}
+
+	after := createTestMessage(t, group.ConversationID, owner.ID, "katılımdan sonra", time.Now())
+	hidden := createTestMessage(t, group.ConversationID, owner.ID, "benden silindi", time.Now())
+	if err := database.DB.Create(&models.MessageHide{MessageID: hidden.ID, UserID: member.ID}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	summary = fetch()
+	if summary.UnreadCount != 1 {
+		t.Errorf("unread_count = %d, want 1 (hidden message excluded)", summary.UnreadCount)
+	}
+	if summary.LastMessage == nil || summary.LastMessage.ID != after.ID {
+		t.Errorf("last_message = %+v, want message %d", summary.LastMessage, after.ID)
+	}
+
+	tombstone := createTestMessage(t, group.ConversationID, owner.ID, "", time.Now())
+	if err := database.DB.Model(&tombstone).Update("deleted_for_everyone_at", time.Now()).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	summary = fetch()
+	if summary.UnreadCount != 1 {
+		t.Errorf("unread_count = %d, want 1 (deleted message excluded)", summary.UnreadCount)
+	}
+}
@@ -0,0 +1,46 @@
+package routes
+
+import (
+	"errors"
+	"log"
+
+	"arcurachat_api/models"
+	"arcurachat_api/utils"
+)
+
+// Mesajların blockchain defterine (Hyperledger Fabric, message_chaincode) kaydedilip kaydedilmeyeceği
+var ledgerEnabled = utils.GetEnvBool("LEDGER_ENABLED", false)
+
+// ✅ Mesajı deftere kaydeden fonksiyon
+// Fabric istemcisi bu paketin bağımlılığı değildir; defteri kullanan dağıtım main içinde atar.
+// Mesaj defterde "msg_<id>" anahtarıyla tutulur, düzenlemede aynı anahtar güncel içerikle yeniden yazılır.
+var LedgerAnchor func(message models.Message) error
+
+// ✅ Defter ayarlarını doğrula
+// LEDGER_ENABLED açıkken LedgerAnchor atanmamışsa mesajlar sessizce defter dışında kalmasın diye hata döner.
+func CheckLedgerConfig() error {
+	if ledgerEnabled && LedgerAnchor == nil {
+		return errors.New("LEDGER_ENABLED açık ama LedgerAnchor tanımlı değil")
+	}
+	return nil
+}
+
+// 🔥 Mesajın güncel içeriğini deftere kaydet
+// Gönderimde ve düzenlemede veritabanı işlemi tamamlandıktan sonra çağrılır;
+// defter hatası mesajı geri almaz, sadece loglanır.
+func anchorMessage(message models.Message) {
+	if !ledgerEnabled || LedgerAnchor == nil {
+		return
+	}
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("Uyarı: Mesaj %d deftere kaydedilirken hata - %v", message.ID, r)
+			}
+		}()
+		if err := LedgerAnchor(message); err != nil {
+			log.Printf("Uyarı: Mesaj %d deftere kaydedilemedi - %v", message.ID, err)
+		}
+	}()
+}
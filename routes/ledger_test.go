@@ -0,0 +1,100 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"arcurachat_api/database"
+	"arcurachat_api/models"
+)
+
+// ✅ Testte defteri aç ve kaydedilen mesajları kanala yönlendir
+func useTestLedger(t *testing.T) <-chan models.Message {
+	t.Helper()
+
+	anchored := make(chan models.Message, 4)
+	previousEnabled, previousAnchor := ledgerEnabled, LedgerAnchor
+	ledgerEnabled = true
+	LedgerAnchor = func(message models.Message) error {
+		anchored <- message
+		return nil
+	}
+	t.Cleanup(func() {
+		ledgerEnabled, LedgerAnchor = previousEnabled, previousAnchor
+	})
+	return anchored
+}
+
+func waitAnchored(t *testing.T, anchored <-chan models.Message) models.Message {
+	t.Helper()
+
+	select {
+	case message := <-anchored:
+		return message
+	case <-time.After(2 * time.Second):
+		t.Fatal("mesaj deftere kaydedilmedi")
+		return models.Message{}
+	}
+}
+
+func TestCheckLedgerConfig(t *testing.T) {
+	previousEnabled, previousAnchor := ledgerEnabled, LedgerAnchor
+	t.Cleanup(func() {
+		ledgerEnabled, LedgerAnchor = previousEnabled, previousAnchor
+	})
+
+	ledgerEnabled, LedgerAnchor = false, nil
+	if err := CheckLedgerConfig(); err != nil {
+		t.Errorf("disabled ledger: unexpected error %v", err)
+	}
+
+	ledgerEnabled = true
+	if err := CheckLedgerConfig(); err == nil {
+		t.Error("enabled ledger without anchor: expected error")
+	}
+
+	LedgerAnchor = func(models.Message) error { return nil }
+	if err := CheckLedgerConfig(); err != nil {
+		t.Errorf("enabled ledger with anchor: unexpected error %v", err)
+	}
+}
+
+func TestAnchorMessageRunsHook(t *testing.T) {
+	anchored := useTestLedger(t)
+
+	message := models.Message{Content: "merhaba"}
+	message.ID = 42
+	anchorMessage(message)
+
+	if got := waitAnchored(t, anchored); got.ID != message.ID || got.Content != message.Content {
+		t.Errorf("anchored %+v, want %+v", got, message)
+	}
+}
+
+// Düzenlenen mesaj güncel içeriğiyle deftere yeniden kaydedilmeli
+func TestEditMessageAnchorsLedger(t *testing.T) {
+	setupTestDB(t)
+	anchored := useTestLedger(t)
+
+	sender := createTestUser(t, "sender")
+	other := createTestUser(t, "other")
+
+	conversation, err := getOrCreateDirectConversation(database.DB, sender.ID, other.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := createTestMessage(t, conversation.ID, sender.ID, "ilk hali", time.Now())
+
+	path := fmt.Sprintf("/messages/%d/edit", message.ID)
+	recorder := performRequest(t, http.MethodPut, "/messages/:message_id/edit", path, sender.ID, map[string]string{"content": "yeni hali"}, EditMessage)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("PUT %s = %d: %s", path, recorder.Code, recorder.Body.String())
+	}
+
+	got := waitAnchored(t, anchored)
+	if got.ID != message.ID || got.Content != "yeni hali" {
+		t.Errorf("anchored message %d with %q, want %d with %q", got.ID, got.Content, message.ID, "yeni hali")
+	}
+}
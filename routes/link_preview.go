@@ -0,0 +1,168 @@
+package routes
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"sync"
+	"time"
+
+	"arcurachat_api/database"
+	"arcurachat_api/models"
+	"arcurachat_api/realtime"
+	"arcurachat_api/unfurl"
+	"arcurachat_api/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm/clause"
+)
+
+// ✅ Mesaj güncelleme olay tipi (bağlantı önizlemesi eklendiğinde)
+const EventMessageUpdated = "message.updated"
+
+var (
+	// Başarılı önizlemelerin önbellekte tutulma süresi
+	linkPreviewTTL = utils.GetEnvDuration("LINK_PREVIEW_TTL", 24*time.Hour)
+	// Getirilemeyen bağlantıların tekrar denenmeden önce beklenme süresi
+	linkPreviewFailureTTL = utils.GetEnvDuration("LINK_PREVIEW_FAILURE_TTL", time.Hour)
+	// Aynı anda getirilebilecek sayfa sayısı
+	linkPreviewWorkers = utils.GetEnvInt("LINK_PREVIEW_WORKERS", 4)
+
+	// Getirilmeyi bekleyen bağlantılar; kuyruk doluysa yeni önizlemeler atlanır
+	linkPreviewQueue = make(chan string, utils.GetEnvInt("LINK_PREVIEW_QUEUE_SIZE", 256))
+
+	// Kuyrukta bekleyen veya getirilen bağlantılar ve önizlemeyi bekleyen mesajlar;
+	// aynı bağlantı birden fazla mesajda geçse de sayfa bir kez getirilir
+	linkPreviewPendingMu sync.Mutex
+	linkPreviewPending   = make(map[string][]models.Message)
+)
+
+// ✅ Mesajdaki ilk bağlantının önizlemesini arka planda hazırla
+// Önizleme hazır olduğunda mesaj güncellenir ve katılımcılara "message.updated" gönderilir.
+func enqueueLinkPreview(message models.Message) {
+	if message.Type != models.MessageTypeUser {
+		return
+	}
+	url := unfurl.FirstURL(message.Content)
+	if url == "" {
+		return
+	}
+
+	linkPreviewPendingMu.Lock()
+	defer linkPreviewPendingMu.Unlock()
+	if waiting, ok := linkPreviewPending[url]; ok {
+		linkPreviewPending[url] = append(waiting, message)
+		return
+	}
+	select {
+	case linkPreviewQueue <- url:
+		linkPreviewPending[url] = []models.Message{message}
+	default:
+		log.Printf("Uyarı: Bağlantı önizleme kuyruğu dolu, mesaj %d için önizleme atlandı", message.ID)
+	}
+}
+
+// 🔥 Bağlantı önizleme çalışanlarını başlat
+func StartLinkPreviewWorkers() {
+	for i := 0; i < linkPreviewWorkers; i++ {
+		go func() {
+			for url := range linkPreviewQueue {
+				runLinkPreviewJob(url)
+			}
+		}()
+	}
+}
+
+// ✅ Bağlantıyı getir ve bekleyen tüm mesajlara önizlemeyi yaz
+// Getirme sırasında kuyruğa eklenen mesajlar da aynı sonucu alır.
+func runLinkPreviewJob(url string) {
+	var (
+		preview models.LinkPreview
+		ok      bool
+	)
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("Hata: Bağlantı önizlemesi hazırlanırken panik (%s) - %v", url, r)
+			}
+		}()
+		preview, ok = linkPreviewFor(url)
+	}()
+
+	linkPreviewPendingMu.Lock()
+	messages := linkPreviewPending[url]
+	delete(linkPreviewPending, url)
+	linkPreviewPendingMu.Unlock()
+
+	if !ok {
+		return
+	}
+	for _, message := range messages {
+		applyLinkPreview(message, preview)
+	}
+}
+
+// ✅ Önizlemeyi mesaja yaz ve katılımcılara bildir
+func applyLinkPreview(message models.Message, preview models.LinkPreview) {
+	data := models.JSONMap{
+		"url":         preview.URL,
+		"title":       preview.Title,
+		"description": preview.Description,
+		"image_url":   preview.ImageURL,
+		"site_name":   preview.SiteName,
+	}
+
+	// Bu arada düzenlenen veya silinen mesajın önizlemesi yazılmaz
+	result := database.DB.Model(&models.Message{}).
+		Where("id = ? AND content = ? AND deleted_for_everyone_at IS NULL", message.ID, message.Content).
+		Update("link_preview", data)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return
+	}
+
+	realtime.DefaultHub.SendToUsers(activeParticipantIDs(message.ConversationID), realtime.Event{
+		Type: EventMessageUpdated,
+		Data: gin.H{"conversation_id": message.ConversationID, "message_id": message.ID, "link_preview": data},
+	})
+}
+
+// 🔥 Bağlantının önizlemesini önbellekten al, yoksa veya süresi dolmuşsa getir
+func linkPreviewFor(url string) (models.LinkPreview, bool) {
+	sum := sha256.Sum256([]byte(url))
+	hash := hex.EncodeToString(sum[:])
+
+	var cached models.LinkPreview
+	if err := database.DB.Where("url_hash = ?", hash).First(&cached).Error; err == nil {
+		ttl := linkPreviewTTL
+		if cached.Failed {
+			ttl = linkPreviewFailureTTL
+		}
+		if time.Since(cached.FetchedAt) < ttl {
+			return cached, !cached.Failed
+		}
+	}
+
+	entry := models.LinkPreview{URLHash: hash, URL: url, FetchedAt: time.Now()}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	preview, err := unfurl.Fetch(ctx, url)
+	if err != nil || preview.Empty() {
+		entry.Failed = true
+		if err != nil {
+			log.Printf("Uyarı: Bağlantı önizlemesi alınamadı (%s) - %v", url, err)
+		}
+	} else {
+		entry.Title = preview.Title
+		entry.Description = preview.Description
+		entry.ImageURL = preview.ImageURL
+		entry.SiteName = preview.SiteName
+	}
+
+	database.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "url_hash"}},
+		DoUpdates: clause.AssignmentColumns([]string{"title", "description", "image_url", "site_name", "fetched_at", "failed", "updated_at"}),
+	}).Create(&entry)
+
+	return entry, !entry.Failed
+}
@@ -0,0 +1,260 @@
+package routes
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"arcurachat_api/database"
+	"arcurachat_api/media"
+	"arcurachat_api/models"
+	"arcurachat_api/realtime"
+	"arcurachat_api/storage"
+	"arcurachat_api/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ✅ Ek işlendi olay tipi
+const EventAttachmentProcessed = "attachment.processed"
+
+var (
+	// Küçük görsellerin en uzun kenar uzunlukları (örn. "96,320,800")
+	thumbnailSizes = parseSizes(utils.GetEnv("THUMBNAIL_SIZES", "96,320,800"))
+	// Aynı anda işlenecek ek sayısı
+	mediaWorkers = utils.GetEnvInt("MEDIA_WORKERS", 2)
+	// Kuyruğa alınamayan veya yarım kalan eklerin yeniden tarandığı aralık
+	mediaSweepInterval = utils.GetEnvDuration("MEDIA_SWEEP_INTERVAL", time.Minute)
+
+	mediaQueue = make(chan uint, 256)
+
+	// Kuyrukta bekleyen veya işlenen ekler (aynı ek iki kez kuyruğa alınmaz)
+	mediaQueuedMu sync.Mutex
+	mediaQueued   = make(map[uint]bool)
+)
+
+func parseSizes(value string) []int {
+	var sizes []int
+	for _, part := range strings.Split(value, ",") {
+		if size, err := strconv.Atoi(strings.TrimSpace(part)); err == nil && size > 0 {
+			sizes = append(sizes, size)
+		}
+	}
+	return sizes
+}
+
+// ✅ Ek görsel veya video ise arka planda işlenmesi gerekir
+func needsMediaProcessing(kind string) bool {
+	return kind == models.AttachmentKindImage || kind == models.AttachmentKindVideo
+}
+
+// ✅ Eki işleme kuyruğuna ekle
+// Kuyruk doluysa ek "processing" durumunda kalır ve periyodik tarama tarafından tekrar alınır.
+func enqueueMediaProcessing(attachmentID uint) {
+	mediaQueuedMu.Lock()
+	defer mediaQueuedMu.Unlock()
+	if mediaQueued[attachmentID] {
+		return
+	}
+	select {
+	case mediaQueue <- attachmentID:
+		mediaQueued[attachmentID] = true
+	default:
+	}
+}
+
+// ✅ Hâlâ işlenmeyi bekleyen ekleri kuyruğa al
+func sweepPendingMedia() {
+	var pending []uint
+	database.DB.Model(&models.Attachment{}).Where("status = ?", models.AttachmentStatusProcessing).
+		Order("id ASC").Pluck("id", &pending)
+	for _, attachmentID := range pending {
+		enqueueMediaProcessing(attachmentID)
+	}
+}
+
+// ✅ Tek bir eki işle; çözücülerdeki olası panik sunucuyu düşürmez
+func runMediaJob(attachmentID uint) {
+	defer func() {
+		mediaQueuedMu.Lock()
+		delete(mediaQueued, attachmentID)
+		mediaQueuedMu.Unlock()
+	}()
+
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panik: %v", r)
+			}
+		}()
+		return processAttachment(attachmentID)
+	}()
+	if err != nil {
+		log.Printf("Hata: Ek %d işlenemedi - %v", attachmentID, err)
+		database.DB.Model(&models.Attachment{}).Where("id = ?", attachmentID).
+			Update("status", models.AttachmentStatusFailed)
+		notifyAttachmentProcessed(attachmentID)
+	}
+}
+
+// 🔥 Medya işleme çalışanlarını başlat
+// Sunucu kapanırken yarım kalan veya kuyruk dolu olduğu için bekleyen ekler periyodik olarak yeniden kuyruğa alınır.
+func StartMediaWorkers() {
+	if !media.VideoToolsAvailable() {
+		log.Println("Uyarı: ffmpeg/ffprobe bulunamadı, metaverisi temizlenemeyen videolar indirilemez (failed) olarak işaretlenecek")
+	}
+
+	for i := 0; i < mediaWorkers; i++ {
+		go func() {
+			for attachmentID := range mediaQueue {
+				runMediaJob(attachmentID)
+			}
+		}()
+	}
+
+	go func() {
+		sweepPendingMedia()
+		ticker := time.NewTicker(mediaSweepInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			sweepPendingMedia()
+		}
+	}()
+}
+
+// 🔥 Eki işle: metaveriyi temizle, boyutları çıkar, küçük görselleri ve BlurHash'i kaydet
+func processAttachment(attachmentID uint) error {
+	var attachment models.Attachment
+	if err := database.DB.First(&attachment, attachmentID).Error; err != nil {
+		return nil // Ek bu arada silinmiş
+	}
+	if attachment.Status != models.AttachmentStatusProcessing {
+		return nil
+	}
+
+	ctx := context.Background()
+	file, err := storage.Default.Open(ctx, attachment.StorageKey)
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(io.LimitReader(file, maxUploadSize+1))
+	file.Close()
+	if err != nil {
+		return err
+	}
+
+	var (
+		sanitized  []byte
+		thumbnails []media.Thumbnail
+		updates    = map[string]interface{}{"status": models.AttachmentStatusReady}
+	)
+	switch attachment.Kind {
+	case models.AttachmentKindImage:
+		result, err := media.ProcessImage(data, attachment.ContentType, thumbnailSizes)
+		if err != nil {
+			return err
+		}
+		sanitized, thumbnails = result.Sanitized, result.Thumbnails
+		updates["width"], updates["height"], updates["blur_hash"] = result.Width, result.Height, result.BlurHash
+	case models.AttachmentKindVideo:
+		// ffmpeg yoksa konum dahil metaveri temizlenemez; video hiçbir zaman temizlenmeden sunulmaz
+		result, err := media.ProcessVideo(data, attachment.ContentType, thumbnailSizes)
+		if err != nil {
+			return err
+		}
+		sanitized, thumbnails = result.Sanitized, result.Thumbnails
+		updates["width"], updates["height"], updates["duration_ms"], updates["blur_hash"] =
+			result.Width, result.Height, result.DurationMs, result.BlurHash
+	}
+
+	// Temizlenmiş dosya orijinalin yerine yazılır; orijinal hiçbir zaman indirilemez
+	if sanitized != nil {
+		if err := storage.Default.Put(ctx, attachment.StorageKey, bytes.NewReader(sanitized), int64(len(sanitized)), attachment.ContentType); err != nil {
+			return err
+		}
+		updates["size"] = int64(len(sanitized))
+	}
+
+	var rows []models.AttachmentThumbnail
+	for _, thumbnail := range thumbnails {
+		key := fmt.Sprintf("%s_%s", attachment.StorageKey, thumbnail.Label)
+		if err := storage.Default.Put(ctx, key, bytes.NewReader(thumbnail.Data), int64(len(thumbnail.Data)), thumbnail.ContentType); err != nil {
+			return err
+		}
+		rows = append(rows, models.AttachmentThumbnail{
+			AttachmentID: attachment.ID,
+			Label:        thumbnail.Label,
+			Width:        thumbnail.Width,
+			Height:       thumbnail.Height,
+			ContentType:  thumbnail.ContentType,
+			Size:         int64(len(thumbnail.Data)),
+			StorageKey:   key,
+		})
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if len(rows) > 0 {
+			if err := tx.Create(&rows).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&attachment).Updates(updates).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	notifyAttachmentProcessed(attachment.ID)
+	return nil
+}
+
+// ✅ İşlenen eki görebilecek kullanıcılara bildir
+// Mesaja bağlı ekler konuşmanın katılımcılarına, bağlanmamış ekler sadece yükleyene gider.
+func notifyAttachmentProcessed(attachmentID uint) {
+	var attachment models.Attachment
+	if err := database.DB.First(&attachment, attachmentID).Error; err != nil {
+		return
+	}
+	attachments := []models.Attachment{attachment}
+	attachThumbnails(attachments)
+
+	recipients := []uint{attachment.UploaderID}
+	if attachment.MessageID != nil {
+		recipients = activeParticipantIDs(attachment.ConversationID)
+	}
+	realtime.DefaultHub.SendToUsers(recipients, realtime.Event{
+		Type: EventAttachmentProcessed,
+		Data: gin.H{"attachment": attachments[0]},
+	})
+}
+
+// ✅ Eklere küçük görsellerini imzalı bağlantılarıyla ekle
+func attachThumbnails(attachments []models.Attachment) {
+	if len(attachments) == 0 {
+		return
+	}
+
+	ids := make([]uint, 0, len(attachments))
+	byID := make(map[uint]int, len(attachments))
+	for i, attachment := range attachments {
+		ids = append(ids, attachment.ID)
+		byID[attachment.ID] = i
+	}
+
+	var thumbnails []models.AttachmentThumbnail
+	database.DB.Where("attachment_id IN ?", ids).Order("width ASC").Find(&thumbnails)
+	for _, thumbnail := range thumbnails {
+		if url, err := storage.Default.SignedURL(thumbnail.StorageKey, attachmentURLTTL); err == nil {
+			thumbnail.URL = url
+		}
+		i := byID[thumbnail.AttachmentID]
+		attachments[i].Thumbnails = append(attachments[i].Thumbnails, thumbnail)
+	}
+}
@@ -0,0 +1,359 @@
+package routes
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"arcurachat_api/database"
+	"arcurachat_api/models"
+	"arcurachat_api/realtime"
+	"arcurachat_api/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ✅ Bildirim olay tipi
+const EventNotification = "notification"
+
+// ✅ Bildirim türleri
+const (
+	NotificationKindMessage = "message"
+	NotificationKindMention = "mention"
+)
+
+// Konuşmadaki herkesi anmak için kullanılan özel isim
+const mentionAllName = "all"
+
+var mentionPattern = regexp.MustCompile(`@([\p{L}\p{N}_.]+)`)
+
+// @all ile anılabilecek en fazla aktif katılımcı sayısı; daha kalabalık konuşmalarda @all genişletilmez
+var mentionAllLimit = utils.GetEnvInt("MENTION_ALL_LIMIT", 256)
+
+// ✅ Konuşmada @all kullanılabilir mi?
+// Kanallarda ve MENTION_ALL_LIMIT üzerindeki gruplarda her katılımcıya bahsetme kaydı yazılmaz.
+func mentionAllAllowed(conversationID uint) bool {
+	if group, ok := groupForConversation(conversationID); ok && group.Type == models.GroupTypeChannel {
+		return false
+	}
+	var count int64
+	database.DB.Model(&models.ConversationParticipant{}).
+		Where("conversation_id = ? AND left_at IS NULL", conversationID).
+		Count(&count)
+	return count <= int64(mentionAllLimit)
+}
+
+// ✅ Kullanıcı adında geçebilecek karakter mi?
+func isMentionRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' || r == '@'
+}
+
+// 🔥 Mesaj içeriğindeki @kullaniciadi ve @all bahsetmelerini çöz
+// Sadece konuşmanın aktif katılımcıları anılabilir; e-posta adresleri gibi kelime içindeki "@" işaretleri atlanır.
+// Gönderen ve gönderenle arasında engel olan kullanıcılar için bahsetme kaydı oluşturulmaz.
+// Kanallarda ve kalabalık gruplarda @all düz metin olarak kalır.
+func parseMentions(conversationID, senderID uint, content string) (models.MessageEntities, []models.MessageMention) {
+	type match struct {
+		offset, length int
+		name           string
+	}
+
+	var matches []match
+	var names []string
+	for _, loc := range mentionPattern.FindAllStringSubmatchIndex(content, -1) {
+		if loc[0] > 0 {
+			if r, _ := utf8.DecodeLastRuneInString(content[:loc[0]]); isMentionRune(r) {
+				continue
+			}
+		}
+		// Cümle sonundaki nokta kullanıcı adına dahil değildir
+		name := strings.TrimRight(content[loc[2]:loc[3]], ".")
+		if name == "" {
+			continue
+		}
+		matches = append(matches, match{
+			offset: utf8.RuneCountInString(content[:loc[0]]),
+			length: utf8.RuneCountInString(name) + 1,
+			name:   name,
+		})
+		names = append(names, name)
+	}
+	if len(matches) == 0 {
+		return nil, nil
+	}
+
+	// Anılan kullanıcı adlarını konuşmanın aktif katılımcılarıyla eşleştir
+	var users []models.User
+	database.DB.Model(&models.User{}).
+		Joins("JOIN conversation_participants cp ON cp.user_id = users.id AND cp.conversation_id = ? AND cp.left_at IS NULL AND cp.deleted_at IS NULL", conversationID).
+		Where("users.username IN ?", names).
+		Find(&users)
+	byName := make(map[string]uint, len(users))
+	for _, user := range users {
+		byName[user.Username] = user.ID
+	}
+
+	allAllowed := false
+	for _, name := range names {
+		if strings.EqualFold(name, mentionAllName) {
+			allAllowed = mentionAllAllowed(conversationID)
+			break
+		}
+	}
+
+	var entities models.MessageEntities
+	mentioned := map[uint]bool{}
+	all := false
+	for _, m := range matches {
+		if userID, ok := byName[m.name]; ok {
+			entities = append(entities, models.MessageEntity{
+				Type: models.EntityTypeMention, Offset: m.offset, Length: m.length, UserID: userID, Username: m.name,
+			})
+			mentioned[userID] = true
+		} else if strings.EqualFold(m.name, mentionAllName) && allAllowed {
+			entities = append(entities, models.MessageEntity{
+				Type: models.EntityTypeMentionAll, Offset: m.offset, Length: m.length,
+			})
+			all = true
+		}
+	}
+
+	recipients := map[uint]bool{}
+	for userID := range mentioned {
+		recipients[userID] = false
+	}
+	if all {
+		for _, userID := range activeParticipantIDs(conversationID) {
+			if _, ok := recipients[userID]; !ok {
+				recipients[userID] = true
+			}
+		}
+	}
+	delete(recipients, senderID)
+
+	var blockers, blocked []uint
+	database.DB.Model(&models.UserBlock{}).Where("blocked_id = ?", senderID).Pluck("blocker_id", &blockers)
+	database.DB.Model(&models.UserBlock{}).Where("blocker_id = ?", senderID).Pluck("blocked_id", &blocked)
+	for _, userID := range append(blockers, blocked...) {
+		delete(recipients, userID)
+	}
+
+	mentions := make([]models.MessageMention, 0, len(recipients))
+	for userID, viaAll := range recipients {
+		mentions = append(mentions, models.MessageMention{
+			UserID:         userID,
+			ConversationID: conversationID,
+			SenderID:       senderID,
+			All:            viaAll,
+		})
+	}
+	return entities, mentions
+}
+
+// 🔥 Mesajın bahsetme kayıtlarını güncelle, yeni anılan kullanıcıları döndür
+// Düzenlemede artık anılmayan kullanıcıların kayıtları silinir, önceden anılanlar okundu bilgisini korur.
+func saveMentions(tx *gorm.DB, message models.Message, mentions []models.MessageMention) ([]uint, error) {
+	var existing []uint
+	if err := tx.Model(&models.MessageMention{}).Where("message_id = ?", message.ID).Pluck("user_id", &existing).Error; err != nil {
+		return nil, err
+	}
+
+	keep := make([]uint, 0, len(mentions))
+	for _, mention := range mentions {
+		keep = append(keep, mention.UserID)
+	}
+	stale := tx.Unscoped().Where("message_id = ?", message.ID)
+	if len(keep) > 0 {
+		stale = stale.Where("user_id NOT IN ?", keep)
+	}
+	if err := stale.Delete(&models.MessageMention{}).Error; err != nil {
+		return nil, err
+	}
+
+	known := make(map[uint]bool, len(existing))
+	for _, userID := range existing {
+		known[userID] = true
+	}
+	var added []uint
+	for _, mention := range mentions {
+		if known[mention.UserID] {
+			continue
+		}
+		mention.MessageID = message.ID
+		if err := tx.Create(&mention).Error; err != nil {
+			return nil, err
+		}
+		added = append(added, mention.UserID)
+	}
+	return added, nil
+}
+
+// 🔥 Yeni mesajı katılımcılara bildir
+// Mesaj senkronizasyonu için olay herkese gönderilir; konuşmayı sessize alan kullanıcıda "muted" alanı
+// işaretlenir ve istemci sesli/görünür bildirim göstermez. Mesajda anılan kullanıcılar sessizde de bildirilir.
+func notifyNewMessage(message models.Message, mentionedIDs []uint) {
+	mentioned := make(map[uint]bool, len(mentionedIDs))
+	for _, userID := range mentionedIDs {
+		mentioned[userID] = true
+	}
+
+	var participants []models.ConversationParticipant
+	database.DB.Where("conversation_id = ? AND left_at IS NULL AND user_id <> ?", message.ConversationID, message.SenderID).
+		Find(&participants)
+
+	now := time.Now()
+	for _, participant := range participants {
+		kind := NotificationKindMessage
+		if mentioned[participant.UserID] {
+			kind = NotificationKindMention
+		}
+		realtime.DefaultHub.SendToUser(participant.UserID, realtime.Event{
+			Type: EventNotification,
+			Data: gin.H{
+				"kind":            kind,
+				"conversation_id": message.ConversationID,
+				"message":         message,
+				"mentioned":       mentioned[participant.UserID],
+				"muted":           participant.IsMuted(now) && !mentioned[participant.UserID],
+			},
+		})
+	}
+}
+
+// ✅ Düzenlenen mesajda yeni anılan kullanıcıları bildir
+func notifyMentions(message models.Message, userIDs []uint) {
+	realtime.DefaultHub.SendToUsers(userIDs, realtime.Event{
+		Type: EventNotification,
+		Data: gin.H{"kind": NotificationKindMention, "conversation_id": message.ConversationID, "message": message, "mentioned": true, "muted": false},
+	})
+}
+
+// ✅ Okunan mesaja kadar olan bahsetmeleri okundu işaretle
+// Başlık mesajları başlığın, diğerleri konuşmanın okuma konumuna göre işaretlenir.
+func markMentionsRead(userID, conversationID uint, threadRootID *uint, upToMessageID uint) {
+	query := database.DB.Model(&models.MessageMention{}).
+		Where("user_id = ? AND conversation_id = ? AND read_at IS NULL AND message_id <= ?", userID, conversationID, upToMessageID)
+	if threadRootID != nil {
+		query = query.Where("message_id IN (SELECT id FROM messages WHERE thread_root_id = ?)", *threadRootID)
+	} else {
+		query = query.Where("message_id IN (SELECT id FROM messages WHERE conversation_id = ? AND thread_root_id IS NULL)", conversationID)
+	}
+	query.Update("read_at", time.Now())
+}
+
+// ✅ Kullanıcının görebildiği bahsetmeler
+func visibleMentions(userID uint) *gorm.DB {
+	return database.DB.Model(&models.MessageMention{}).
+		Joins("JOIN messages ON messages.id = message_mentions.message_id AND messages.deleted_at IS NULL AND messages.deleted_for_everyone_at IS NULL").
+		Where("message_mentions.user_id = ?", userID).
+		Scopes(accessibleMessages(userID))
+}
+
+// 🔥 Bahsetmelerim (GET /mentions?unread=true&conversation_id=)
+func GetMentions(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	query := visibleMentions(userID.(uint))
+	if conversationID := c.Query("conversation_id"); conversationID != "" {
+		query = query.Where("message_mentions.conversation_id = ?", conversationID)
+	}
+
+	var unreadCount int64
+	query.Session(&gorm.Session{}).Where("message_mentions.read_at IS NULL").Count(&unreadCount)
+
+	if c.Query("unread") == "true" {
+		query = query.Where("message_mentions.read_at IS NULL")
+	}
+
+	page, limit := getPagination(c)
+	var mentions []models.MessageMention
+	if err := query.Select("message_mentions.*").
+		Order("message_mentions.id DESC").
+		Offset((page - 1) * limit).Limit(limit).
+		Find(&mentions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Bahsetmeler alınamadı"})
+		return
+	}
+
+	messageIDs := make([]uint, 0, len(mentions))
+	for _, mention := range mentions {
+		messageIDs = append(messageIDs, mention.MessageID)
+	}
+	var messages []models.Message
+	if len(messageIDs) > 0 {
+		database.DB.Where("id IN ?", messageIDs).Find(&messages)
+	}
+	attachQuotes(messages, userID.(uint))
+	attachReactions(messages, userID.(uint))
+	attachAttachments(messages)
+	byID := make(map[uint]models.Message, len(messages))
+	for _, message := range messages {
+		byID[message.ID] = message
+	}
+
+	data := make([]gin.H, 0, len(mentions))
+	for _, mention := range mentions {
+		data = append(data, gin.H{
+			"id":              mention.ID,
+			"conversation_id": mention.ConversationID,
+			"sender_id":       mention.SenderID,
+			"all":             mention.All,
+			"read":            mention.ReadAt != nil,
+			"read_at":         mention.ReadAt,
+			"created_at":      mention.CreatedAt,
+			"message":         byID[mention.MessageID],
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":         data,
+		"unread_count": unreadCount,
+		"page":         page,
+		"limit":        limit,
+	})
+}
+
+// ✅ Bahsetmeleri okundu işaretle (POST /mentions/read)
+// conversation_id verilirse sadece o konuşmadaki bahsetmeler işaretlenir.
+func MarkMentionsAsRead(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	var input struct {
+		ConversationID uint `json:"conversation_id"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz veri"})
+			return
+		}
+	}
+
+	query := database.DB.Model(&models.MessageMention{}).Where("user_id = ? AND read_at IS NULL", userID)
+	if input.ConversationID != 0 {
+		query = query.Where("conversation_id = ?", input.ConversationID)
+	}
+	if err := query.Update("read_at", time.Now()).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Bahsetmeler okundu olarak işaretlenemedi"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Bahsetmeler okundu olarak işaretlendi"})
+}
+
+// ✅ Bahsetme route'larını kaydet
+func RegisterMentionRoutes(router *gin.Engine) {
+	router.GET("/mentions", AuthMiddleware(), GetMentions)
+	router.POST("/mentions/read", AuthMiddleware(), MarkMentionsAsRead)
+}
@@ -0,0 +1,48 @@
+package routes
+
+import (
+	"testing"
+
+	"arcurachat_api/database"
+	"arcurachat_api/models"
+
+	"gorm.io/gorm"
+)
+
+// Kanallarda @all her aboneye bahsetme kaydı oluşturmamalı
+func TestParseMentionsSkipsAllInChannel(t *testing.T) {
+	setupTestDB(t)
+
+	owner := createTestUser(t, "owner")
+	subscriber := createTestUser(t, "subscriber")
+
+	channel := models.Group{Name: "Duyurular", Type: models.GroupTypeChannel, OwnerID: owner.ID}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&channel).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&models.GroupMember{GroupID: channel.ID, UserID: owner.ID, Role: models.GroupRoleOwner}).Error; err != nil {
+			return err
+		}
+		return ensureGroupConversation(tx, &channel)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := addParticipant(database.DB, channel.ConversationID, subscriber.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	entities, mentions := parseMentions(channel.ConversationID, owner.ID, "@all yeni duyuru")
+	if len(mentions) != 0 {
+		t.Errorf("got %d mentions, want none for @all in a channel", len(mentions))
+	}
+	if len(entities) != 0 {
+		t.Errorf("got %d entities, want @all left as plain text", len(entities))
+	}
+
+	_, mentions = parseMentions(channel.ConversationID, owner.ID, "@"+subscriber.Username+" merhaba")
+	if len(mentions) != 1 || mentions[0].UserID != subscriber.ID {
+		t.Errorf("got mentions %+v, want subscriber %d", mentions, subscriber.ID)
+	}
+}
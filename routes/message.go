@@ -1,25 +1,56 @@
 package routes
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"arcurachat_api/database"
 	"arcurachat_api/models"
+	"arcurachat_api/realtime"
+	"arcurachat_api/utils"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	
 )
 
+// ✅ Mesaj silme olay tipi
+const EventMessageDeleted = "message.deleted"
+
+var (
+	// Mesajın gönderildikten sonra düzenlenebileceği süre
+	messageEditWindow = utils.GetEnvDuration("MESSAGE_EDIT_WINDOW", 48*time.Hour)
+	// Gönderenin mesajı herkesten silebileceği süre
+	deleteForEveryoneWindow = utils.GetEnvDuration("DELETE_FOR_EVERYONE_WINDOW", 48*time.Hour)
+)
+
+var (
+	errMessageDeleted    = errors.New("mesaj silinmiş")
+	errEditWindowExpired = errors.New("düzenleme süresi doldu")
+)
+
 func RegisterMessageRoutes(router *gin.Engine) {
 	messageRoutes := router.Group("/messages")
 	messageRoutes.Use(AuthMiddleware())
 
 	messageRoutes.POST("/send", SendMessage)                   // 🔥 Mesaj gönderme
+	messageRoutes.POST("/scheduled", ScheduleMessage)                       // 🔥 Mesajı zamanla
+	messageRoutes.GET("/scheduled", GetScheduledMessages)                   // 🔥 Zamanlanmış mesajlar
+	messageRoutes.PUT("/scheduled/:scheduled_id", UpdateScheduledMessage)    // 🔥 Zamanlanmış mesajı düzenle
+	messageRoutes.DELETE("/scheduled/:scheduled_id", CancelScheduledMessage) // 🔥 Zamanlanmış mesajı iptal et
 	messageRoutes.GET("/:conversation_id", GetMessagesByConversation) // 🔥 Belirli konuşmanın mesajlarını getir
 	messageRoutes.DELETE("/:message_id", DeleteMessage)       // 🔥 Mesajı sil
 	messageRoutes.PUT("/:message_id/edit", EditMessage)       // 🔥 Mesajı düzenle
+	messageRoutes.GET("/history/:message_id", GetMessageHistory) // 🔥 Düzenleme geçmişi
 	messageRoutes.POST("/:message_id/read", MarkMessageAsRead) // 🔥 Mesajı okundu olarak işaretle
+	messageRoutes.POST("/:message_id/reactions", AddReaction)           // 🔥 Tepki ekle
+	messageRoutes.DELETE("/:message_id/reactions/:emoji", RemoveReaction) // 🔥 Tepkiyi kaldır
+	messageRoutes.POST("/:message_id/pin", PinMessage)                    // 🔥 Mesajı sabitle
+	messageRoutes.DELETE("/:message_id/pin", UnpinMessage)                // 🔥 Sabitlemeyi kaldır
 }
 
 // // 🔥 Mesaj Gönderme (Hem PostgreSQL'e Hem de Blockchain'e)
@@ -111,6 +142,99 @@ func RegisterMessageRoutes(router *gin.Engine) {
 // }
 
 
+// ✅ Mesaj gönderme girdisi
+type sendMessageInput struct {
+	ConversationID uint   `json:"conversation_id"`
+	Content        string `json:"content"`
+	ReplyToID      *uint  `json:"reply_to_id"`    // Alıntılanacak mesaj
+	ThreadRootID   *uint  `json:"thread_root_id"` // Yanıt bir başlığa gönderilecekse kök mesaj
+	AttachmentIDs  []uint `json:"attachment_ids"` // POST /attachments ile yüklenmiş ekler
+}
+
+// 🔥 Mesajı yetkileri kontrol ederek kaydet
+// POST /messages/send ve zamanlanmış mesajlar aynı yolu kullanır. Kayıt verilen tx içinde yapılır;
+// bildirimler commit sonrasında publishMessage ile gönderilmelidir. Gönderilemezse HTTP durum kodu ve nedeni döner.
+func sendMessage(db *gorm.DB, userID uint, input sendMessageInput) (models.Message, []uint, int, string) {
+	var message models.Message
+
+	// 🔥 Sadece konuşmanın aktif katılımcıları mesaj gönderebilir
+	if !isActiveParticipant(input.ConversationID, userID) {
+		return message, nil, http.StatusForbidden, "Bu konuşmaya mesaj göndermeye yetkiniz yok"
+	}
+
+	// 🔥 Engellenen kullanıcıyla birebir mesajlaşılamaz
+	if isDirectConversationBlocked(input.ConversationID, userID) {
+		return message, nil, http.StatusForbidden, "Bu kullanıcıya mesaj gönderemezsiniz"
+	}
+
+	// 🔥 Grup ayarları (sadece yöneticiler, yavaş mod)
+	if status, reason := groupPostRestriction(input.ConversationID, userID); status != 0 {
+		return message, nil, status, reason
+	}
+
+	if len(input.AttachmentIDs) > maxAttachmentsPerMessage {
+		return message, nil, http.StatusBadRequest, fmt.Sprintf("Bir mesaja en fazla %d ek eklenebilir", maxAttachmentsPerMessage)
+	}
+
+	// 🔥 Yanıtlanan mesaj ve başlık aynı konuşmada olmalı
+	replyTo, status, reason := validateReplyTargets(input.ConversationID, userID, input.ReplyToID, input.ThreadRootID)
+	if status != 0 {
+		return message, nil, status, reason
+	}
+
+	message = models.Message{
+		ConversationID: input.ConversationID,
+		SenderID:       userID,
+		Content:        input.Content,
+		IsRead:         false,
+		ThreadRootID:   input.ThreadRootID,
+	}
+	if replyTo != nil {
+		message.ReplyToID = &replyTo.ID
+		message.Quote = quoteSnapshot(*replyTo)
+	}
+
+	// 🔥 @kullaniciadi ve @all bahsetmeleri
+	entities, mentions := parseMentions(input.ConversationID, userID, input.Content)
+	message.Entities = entities
+
+	var mentionedIDs []uint
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&message).Error; err != nil {
+			return err
+		}
+		if err := linkAttachments(tx, message, input.AttachmentIDs); err != nil {
+			return err
+		}
+		var err error
+		if mentionedIDs, err = saveMentions(tx, message, mentions); err != nil {
+			return err
+		}
+		// Kendi yanıtı başlıkta okunmamış sayılmaz
+		if message.ThreadRootID != nil {
+			return advanceThreadRead(tx, *message.ThreadRootID, userID, message.ID)
+		}
+		return nil
+	})
+	if errors.Is(err, errInvalidAttachments) {
+		return message, nil, http.StatusBadRequest, "Ekler bulunamadı veya başka bir mesajda kullanılmış"
+	}
+	if err != nil {
+		return message, nil, http.StatusInternalServerError, "Mesaj gönderilemedi"
+	}
+
+	messages := []models.Message{message}
+	attachAttachments(messages)
+	return messages[0], mentionedIDs, 0, ""
+}
+
+// ✅ Kaydedilen mesajı katılımcılara bildir, bağlantı önizlemesini başlat ve deftere kaydet
+func publishMessage(message models.Message, mentionedIDs []uint) {
+	notifyNewMessage(message, mentionedIDs)
+	enqueueLinkPreview(message)
+	anchorMessage(message)
+}
+
 // 🔥 1. Mesaj Gönderme (POST /messages/send)
 func SendMessage(c *gin.Context) {
 	userID, exists := c.Get("userID")
@@ -119,45 +243,64 @@ func SendMessage(c *gin.Context) {
 		return
 	}
 
-	var input struct {
-		ConversationID uint   `json:"conversation_id"`
-		Content        string `json:"content"`
-	}
-
+	var input sendMessageInput
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz veri"})
 		return
 	}
 
-	message := models.Message{
-		ConversationID: input.ConversationID,
-		SenderID:       userID.(uint),
-		Content:        input.Content,
-		IsRead:         false,
-	}
-
-	if err := database.DB.Create(&message).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Mesaj gönderilemedi"})
+	message, mentionedIDs, status, reason := sendMessage(database.DB, userID.(uint), input)
+	if status != 0 {
+		c.JSON(status, gin.H{"error": reason})
 		return
 	}
 
+	// Mesaj gönderilince "yazıyor" göstergesi kapanır
+	clearSignal(input.ConversationID, userID.(uint))
+	publishMessage(message, mentionedIDs)
+
 	c.JSON(http.StatusOK, gin.H{"message": "Mesaj başarıyla gönderildi", "data": message})
 }
 
 // 🔥 2. Belirli Bir Konuşmanın Mesajlarını Getir (GET /messages/:conversation_id)
 func GetMessagesByConversation(c *gin.Context) {
-	conversationID := c.Param("conversation_id")
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	conversationID, err := strconv.ParseUint(c.Param("conversation_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz konuşma ID"})
+		return
+	}
+
+	if !hasConversationAccess(uint(conversationID), userID.(uint)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Bu konuşmayı görüntülemeye yetkiniz yok"})
+		return
+	}
 
+	// 🔥 Çıkarılmış üyeler yalnızca çıkarıldıkları ana kadar olan mesajları görür
+	// Başlık yanıtları ana akışta değil, kök mesajın özetinde gösterilir
 	var messages []models.Message
-	if err := database.DB.Where("conversation_id = ?", conversationID).Find(&messages).Error; err != nil {
+	if err := database.DB.Scopes(accessibleMessages(userID.(uint))).Where("conversation_id = ? AND thread_root_id IS NULL", conversationID).Find(&messages).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Mesajlar alınamadı"})
 		return
 	}
 
+	attachQuotes(messages, userID.(uint))
+	attachThreadSummaries(messages, userID.(uint))
+	attachReactions(messages, userID.(uint))
+	attachAttachments(messages)
+
 	c.JSON(http.StatusOK, gin.H{"data": messages})
 }
 
-// 🔥 3. Mesajı Silme (DELETE /messages/:message_id)
+// 🔥 3. Mesajı Silme (DELETE /messages/:message_id?scope=me|everyone)
+// "me" mesajı sadece kullanıcıdan gizler. "everyone" içeriği herkes için temizleyip tombstone bırakır;
+// gönderen bunu DELETE_FOR_EVERYONE_WINDOW süresi içinde, grup yöneticileri ise süre sınırı olmadan yapabilir.
+// scope verilmezse eski istemcilerle uyum için "everyone" uygulanır.
 func DeleteMessage(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
@@ -168,22 +311,112 @@ func DeleteMessage(c *gin.Context) {
 	messageID := c.Param("message_id")
 	var message models.Message
 
-	if err := database.DB.First(&message, messageID).Error; err != nil {
+	if err := database.DB.First(&message, messageID).Error; err != nil || !canViewMessage(message, userID.(uint)) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Mesaj bulunamadı"})
 		return
 	}
 
-	// Sadece mesajın sahibi silebilir
-	if message.SenderID != userID.(uint) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Bu mesajı silmeye yetkiniz yok"})
-		return
+	switch c.DefaultQuery("scope", "everyone") {
+	case "me":
+		hide := models.MessageHide{MessageID: message.ID, UserID: userID.(uint)}
+		if err := database.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&hide).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Mesaj silinemedi"})
+			return
+		}
+
+		// Kullanıcının diğer cihazları da mesajı kaldırsın
+		realtime.DefaultHub.SendToUser(userID.(uint), realtime.Event{
+			Type: EventMessageDeleted,
+			Data: gin.H{"conversation_id": message.ConversationID, "message_id": message.ID, "scope": "me"},
+		})
+		c.JSON(http.StatusOK, gin.H{"message": "Mesaj sizden silindi"})
+	case "everyone":
+		if message.DeletedForEveryoneAt != nil {
+			c.JSON(http.StatusOK, gin.H{"message": "Mesaj zaten herkesten silinmiş", "data": message})
+			return
+		}
+		if status, reason := deleteForEveryoneRestriction(message, userID.(uint)); status != 0 {
+			c.JSON(status, gin.H{"error": reason})
+			return
+		}
+
+		if err := database.DB.Transaction(func(tx *gorm.DB) error {
+			return tombstoneMessage(tx, &message, userID.(uint))
+		}); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Mesaj silinemedi"})
+			return
+		}
+
+		realtime.DefaultHub.SendToUsers(activeParticipantIDs(message.ConversationID), realtime.Event{
+			Type: EventMessageDeleted,
+			Data: gin.H{"conversation_id": message.ConversationID, "message_id": message.ID, "scope": "everyone", "message": message},
+		})
+		c.JSON(http.StatusOK, gin.H{"message": "Mesaj herkesten silindi", "data": message})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "scope me veya everyone olmalıdır"})
 	}
+}
 
-	database.DB.Delete(&message)
-	c.JSON(http.StatusOK, gin.H{"message": "Mesaj başarıyla silindi"})
+// ✅ Kullanıcı mesajı herkesten silebilir mi?
+func deleteForEveryoneRestriction(message models.Message, userID uint) (int, string) {
+	if message.Type != models.MessageTypeUser {
+		return http.StatusForbidden, "Sistem mesajları silinemez"
+	}
+
+	// 🔥 Grup yöneticileri moderasyon yetkisi olan üyelerin mesajlarını süre sınırı olmadan silebilir
+	if message.SenderID != userID {
+		if group, ok := groupForConversation(message.ConversationID); ok {
+			if canModerateUser(group, groupRole(group, userID), message.SenderID) {
+				return 0, ""
+			}
+		}
+		return http.StatusForbidden, "Bu mesajı silmeye yetkiniz yok"
+	}
+
+	if time.Since(message.CreatedAt) > deleteForEveryoneWindow {
+		return http.StatusForbidden, "Mesajın herkesten silinme süresi doldu"
+	}
+	return 0, ""
+}
+
+// 🔥 Mesajı tombstone'a çevir
+// İçerik, ekler, düzenleme geçmişi, tepkiler, bahsetmeler, sabitleme ve bu mesajı alıntılayan yanıtlardaki kopyalar temizlenir.
+func tombstoneMessage(tx *gorm.DB, message *models.Message, actorID uint) error {
+	if err := tx.Model(message).Updates(map[string]interface{}{
+		"content":                 "",
+		"payload":                 nil,
+		"quote":                   nil,
+		"link_preview":            nil,
+		"entities":                nil,
+		"deleted_for_everyone_at": time.Now(),
+		"deleted_by":              actorID,
+	}).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Where("message_id = ?", message.ID).Delete(&models.MessageRevision{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Where("message_id = ?", message.ID).Delete(&models.MessageReaction{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Where("message_id = ?", message.ID).Delete(&models.MessageMention{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Where("message_id = ?", message.ID).Delete(&models.PinnedMessage{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(&models.Message{}).Where("reply_to_id = ?", message.ID).
+		Update("quote", models.JSONMap{"message_id": message.ID, "sender_id": message.SenderID, "deleted": true}).Error; err != nil {
+		return err
+	}
+	if err := purgeAttachments(tx, message.ID); err != nil {
+		return err
+	}
+	return tx.First(message, message.ID).Error
 }
 
 // 🔥 4. Mesajı Düzenleme (PUT /messages/:message_id/edit)
+// Önceki içerik revizyon olarak saklanır; düzenleme sadece MESSAGE_EDIT_WINDOW süresi içinde yapılabilir.
 func EditMessage(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
@@ -201,13 +434,29 @@ func EditMessage(c *gin.Context) {
 	}
 
 	// 🔥 Sadece mesajın sahibi düzenleyebilir
-	if message.SenderID != userID.(uint) {
+	if message.SenderID != userID.(uint) || message.Type != models.MessageTypeUser || message.DeletedForEveryoneAt != nil {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Bu mesajı düzenlemeye yetkiniz yok"})
 		return
 	}
 
+	// 🔥 Düzenleme süresi dolmuşsa mesaj değiştirilemez
+	if time.Since(message.CreatedAt) > messageEditWindow {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Mesajın düzenleme süresi doldu"})
+		return
+	}
+
+	// 🔥 Sohbetten ayrılmış ya da gruptan yasaklanmış kullanıcılar mesajlarını düzenleyemez
+	if !isActiveParticipant(message.ConversationID, userID.(uint)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Bu sohbetin katılımcısı değilsiniz"})
+		return
+	}
+	if group, ok := groupForConversation(message.ConversationID); ok && isBannedFromGroup(group.ID, userID.(uint)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Bu gruptan yasaklandınız"})
+		return
+	}
+
 	var input struct {
-		Content string `json:"content"`
+		Content string `json:"content" binding:"required"`
 	}
 
 	// JSON formatındaki yeni içerik verisini al
@@ -216,25 +465,131 @@ func EditMessage(c *gin.Context) {
 		return
 	}
 
-	// Mesaj içeriğini güncelle
-	database.DB.Model(&message).Update("content", input.Content)
+	entities, mentions := parseMentions(message.ConversationID, userID.(uint), input.Content)
+
+	changed := false
+	var addedMentions []uint
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// Eşzamanlı düzenlemelerde revizyonların kaybolmaması için satır kilitlenir
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&message, message.ID).Error; err != nil {
+			return err
+		}
+		// Kilit beklenirken mesaj herkesten silinmiş ya da süre dolmuş olabilir
+		if message.DeletedForEveryoneAt != nil {
+			return errMessageDeleted
+		}
+		if time.Since(message.CreatedAt) > messageEditWindow {
+			return errEditWindowExpired
+		}
+		if message.Content == input.Content {
+			return nil
+		}
+
+		revision := models.MessageRevision{
+			MessageID: message.ID,
+			Content:   message.Content,
+			EditedBy:  userID.(uint),
+		}
+		if err := tx.Create(&revision).Error; err != nil {
+			return err
+		}
+
+		// Eski önizleme yeni içerikle uyuşmayabilir; gerekirse yeniden hazırlanır
+		now := time.Now()
+		if err := tx.Model(&message).Updates(map[string]interface{}{
+			"content":      input.Content,
+			"edited_at":    now,
+			"edit_count":   gorm.Expr("edit_count + 1"),
+			"link_preview": nil,
+			"entities":     entities,
+		}).Error; err != nil {
+			return err
+		}
+		changed = true
+
+		// Yeni anılan kullanıcılar bildirilir, artık anılmayanların kayıtları silinir
+		var err error
+		if addedMentions, err = saveMentions(tx, message, mentions); err != nil {
+			return err
+		}
+
+		// Güncel değerleri yanıtta döndürmek için mesajı yeniden oku
+		return tx.First(&message, message.ID).Error
+	})
+	if errors.Is(err, errMessageDeleted) {
+		c.JSON(http.StatusGone, gin.H{"error": "Mesaj silinmiş"})
+		return
+	}
+	if errors.Is(err, errEditWindowExpired) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Mesajın düzenleme süresi doldu"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Mesaj güncellenemedi"})
+		return
+	}
+
+	if changed {
+		notifyMentions(message, addedMentions)
+		enqueueLinkPreview(message)
+		anchorMessage(message)
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Mesaj başarıyla güncellendi",
 		"data": gin.H{
-			"id":             message.ID,
+			"id":              message.ID,
 			"conversation_id": message.ConversationID,
-			"sender_id":      message.SenderID,
-			"content":        message.Content,
-			"is_read":        message.IsRead,
-			"read_at":        message.ReadAt,
+			"sender_id":       message.SenderID,
+			"content":         message.Content,
+			"is_read":         message.IsRead,
+			"read_at":         message.ReadAt,
+			"edited_at":       message.EditedAt,
+			"edit_count":      message.EditCount,
+			"entities":        message.Entities,
 		},
 	})
 }
 
+// ✅ Mesajın Düzenleme Geçmişi (GET /messages/history/:message_id)
+func GetMessageHistory(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	var message models.Message
+	if err := database.DB.First(&message, c.Param("message_id")).Error; err != nil || !canViewMessage(message, userID.(uint)) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Mesaj bulunamadı"})
+		return
+	}
+
+	var revisions []models.MessageRevision
+	if err := database.DB.Where("message_id = ?", message.ID).Order("id ASC").Find(&revisions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Düzenleme geçmişi alınamadı"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"message_id": message.ID,
+			"content":    message.Content,
+			"edited_at":  message.EditedAt,
+			"edit_count": message.EditCount,
+			"revisions":  revisions,
+		},
+	})
+}
 
 // 🔥 5. Mesajı Okundu Olarak İşaretleme (POST /messages/:message_id/read)
 func MarkMessageAsRead(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
 	messageID := c.Param("message_id")
 	var message models.Message
 
@@ -243,6 +598,22 @@ func MarkMessageAsRead(c *gin.Context) {
 		return
 	}
 
+	if !canViewMessage(message, userID.(uint)) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Mesaj bulunamadı"})
+		return
+	}
+
+	// 🔥 Kullanıcının okuma konumunu ilerlet (okunmamış sayısı buna göre hesaplanır)
+	// Başlık yanıtları konuşmanın değil, başlığın okuma konumunu ilerletir
+	if message.ThreadRootID != nil {
+		advanceThreadRead(database.DB, *message.ThreadRootID, userID.(uint), message.ID)
+	} else {
+		database.DB.Model(&models.ConversationParticipant{}).
+			Where("conversation_id = ? AND user_id = ? AND left_at IS NULL AND last_read_message_id < ?", message.ConversationID, userID, message.ID).
+			Update("last_read_message_id", message.ID)
+	}
+	markMentionsRead(userID.(uint), message.ConversationID, message.ThreadRootID, message.ID)
+
 	// Zaten okunmuşsa işlem yapma
 	if message.IsRead {
 		c.JSON(http.StatusOK, gin.H{"message": "Mesaj zaten okunmuş"})
@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"arcurachat_api/database"
+)
+
+// Konuşmadan ayrılan kullanıcı eski mesajlarını düzenleyememeli
+func TestEditMessageRejectsFormerParticipant(t *testing.T) {
+	setupTestDB(t)
+
+	sender := createTestUser(t, "sender")
+	other := createTestUser(t, "other")
+
+	conversation, err := getOrCreateDirectConversation(database.DB, sender.ID, other.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := createTestMessage(t, conversation.ID, sender.ID, "ilk hali", time.Now())
+
+	if err := removeParticipant(database.DB, conversation.ID, sender.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	path := fmt.Sprintf("/messages/%d/edit", message.ID)
+	recorder := performRequest(t, http.MethodPut, "/messages/:message_id/edit", path, sender.ID, map[string]string{"content": "yeni hali"}, EditMessage)
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("PUT %s = %d, want %d: %s", path, recorder.Code, http.StatusForbidden, recorder.Body.String())
+	}
+}
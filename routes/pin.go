@@ -0,0 +1,218 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"arcurachat_api/database"
+	"arcurachat_api/models"
+	"arcurachat_api/realtime"
+	"arcurachat_api/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ✅ Sabitleme olay tipi
+const EventMessagePin = "message.pin"
+
+// Bir konuşmada aynı anda sabitlenebilecek en fazla mesaj sayısı
+var maxPinnedMessages = utils.GetEnvInt("MAX_PINNED_MESSAGES", 10)
+
+var errPinLimitReached = errors.New("sabitleme sınırına ulaşıldı")
+
+// 🔥 Kullanıcı konuşmada mesaj sabitleyebilir mi?
+// Grup konuşmalarında grup ayarları (yöneticiler / members_can_pin), birebir konuşmalarda iki taraf da sabitleyebilir.
+func pinRestriction(conversationID, userID uint) (int, string) {
+	if !isActiveParticipant(conversationID, userID) {
+		return http.StatusForbidden, "Bu konuşmaya erişim yetkiniz yok"
+	}
+	if group, ok := groupForConversation(conversationID); ok {
+		if !canPinInGroup(group, groupRole(group, userID)) {
+			return http.StatusForbidden, "Bu grupta mesaj sabitleme yetkiniz yok"
+		}
+		return 0, ""
+	}
+	if isDirectConversationBlocked(conversationID, userID) {
+		return http.StatusForbidden, "Bu konuşmada mesaj sabitleyemezsiniz"
+	}
+	return 0, ""
+}
+
+// ✅ URL'deki mesajı getir ve sabitleme yetkisini kontrol et
+func pinnableMessage(c *gin.Context, userID uint) (models.Message, bool) {
+	var message models.Message
+	if err := database.DB.First(&message, c.Param("message_id")).Error; err != nil || !canViewMessage(message, userID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Mesaj bulunamadı"})
+		return message, false
+	}
+	if message.Type != models.MessageTypeUser || message.DeletedForEveryoneAt != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Bu mesaj sabitlenemez"})
+		return message, false
+	}
+	if status, reason := pinRestriction(message.ConversationID, userID); status != 0 {
+		c.JSON(status, gin.H{"error": reason})
+		return message, false
+	}
+	return message, true
+}
+
+// ✅ Sabitleme değişikliğini katılımcılara bildir
+func broadcastPin(message models.Message, pinned bool, userID uint) {
+	realtime.DefaultHub.SendToUsers(activeParticipantIDs(message.ConversationID), realtime.Event{
+		Type: EventMessagePin,
+		Data: gin.H{"conversation_id": message.ConversationID, "message_id": message.ID, "pinned": pinned, "user_id": userID},
+	})
+}
+
+// 🔥 Mesajı Sabitle (POST /messages/:message_id/pin)
+// Sınır kontrolünün eşzamanlı isteklerde aşılmaması için konuşma satırı kilitlenir.
+func PinMessage(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	message, ok := pinnableMessage(c, userID.(uint))
+	if !ok {
+		return
+	}
+
+	var pin models.PinnedMessage
+	created := false
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var conversation models.Conversation
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&conversation, message.ConversationID).Error; err != nil {
+			return err
+		}
+
+		// Zaten sabitlenmişse bir şey yapma
+		if err := tx.Where("message_id = ?", message.ID).First(&pin).Error; err == nil {
+			return nil
+		}
+
+		var count int64
+		if err := tx.Model(&models.PinnedMessage{}).Where("conversation_id = ?", message.ConversationID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= int64(maxPinnedMessages) {
+			return errPinLimitReached
+		}
+
+		pin = models.PinnedMessage{ConversationID: message.ConversationID, MessageID: message.ID, PinnedBy: userID.(uint)}
+		if err := tx.Create(&pin).Error; err != nil {
+			return err
+		}
+		created = true
+		return createSystemMessage(tx, message.ConversationID, models.SystemEventMessagePinned, models.JSONMap{
+			"message_id": message.ID,
+			"user_id":    userID.(uint),
+		})
+	})
+	if errors.Is(err, errPinLimitReached) {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Bir konuşmada en fazla %d mesaj sabitlenebilir", maxPinnedMessages)})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Mesaj sabitlenemedi"})
+		return
+	}
+
+	if !created {
+		c.JSON(http.StatusOK, gin.H{"message": "Mesaj zaten sabitlenmiş", "data": pin})
+		return
+	}
+
+	broadcastPin(message, true, userID.(uint))
+	c.JSON(http.StatusOK, gin.H{"message": "Mesaj sabitlendi", "data": pin})
+}
+
+// 🔥 Sabitlemeyi Kaldır (DELETE /messages/:message_id/pin)
+func UnpinMessage(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	message, ok := pinnableMessage(c, userID.(uint))
+	if !ok {
+		return
+	}
+
+	result := database.DB.Unscoped().Where("message_id = ?", message.ID).Delete(&models.PinnedMessage{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Sabitleme kaldırılamadı"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Mesaj sabitlenmemiş"})
+		return
+	}
+
+	broadcastPin(message, false, userID.(uint))
+	c.JSON(http.StatusOK, gin.H{"message": "Sabitleme kaldırıldı"})
+}
+
+// ✅ Sabitlenmiş Mesajlar (GET /conversations/:id/pins)
+// En son sabitlenen mesaj önce gelir; kullanıcının göremediği mesajlar listelenmez.
+func GetPinnedMessages(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	conversationID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz konuşma ID"})
+		return
+	}
+	if !hasConversationAccess(uint(conversationID), userID.(uint)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Bu konuşmaya erişim yetkiniz yok"})
+		return
+	}
+
+	var pins []models.PinnedMessage
+	if err := database.DB.Model(&models.PinnedMessage{}).
+		Joins("JOIN messages ON messages.id = pinned_messages.message_id AND messages.deleted_at IS NULL").
+		Where("pinned_messages.conversation_id = ?", conversationID).
+		Scopes(accessibleMessages(userID.(uint))).
+		Select("pinned_messages.*").
+		Order("pinned_messages.id DESC").
+		Find(&pins).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Sabitlenmiş mesajlar alınamadı"})
+		return
+	}
+
+	messageIDs := make([]uint, 0, len(pins))
+	for _, pin := range pins {
+		messageIDs = append(messageIDs, pin.MessageID)
+	}
+	var messages []models.Message
+	if len(messageIDs) > 0 {
+		database.DB.Where("id IN ?", messageIDs).Find(&messages)
+	}
+	attachQuotes(messages, userID.(uint))
+	attachReactions(messages, userID.(uint))
+	attachAttachments(messages)
+	byID := make(map[uint]models.Message, len(messages))
+	for _, message := range messages {
+		byID[message.ID] = message
+	}
+
+	data := make([]gin.H, 0, len(pins))
+	for _, pin := range pins {
+		data = append(data, gin.H{
+			"pinned_by": pin.PinnedBy,
+			"pinned_at": pin.CreatedAt,
+			"message":   byID[pin.MessageID],
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": data, "max_pins": maxPinnedMessages})
+}
@@ -0,0 +1,141 @@
+package routes
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"arcurachat_api/database"
+	"arcurachat_api/models"
+	"arcurachat_api/realtime"
+
+	"github.com/gin-gonic/gin"
+)
+
+// En fazla bu kadar kullanıcının durumu tek istekte sorgulanabilir
+const maxPresenceQuery = 100
+
+// ✅ İzleyici kullanıcının çevrimiçi durumunu görebilir mi?
+func canSeePresence(ownerID, viewerID uint) bool {
+	if ownerID == viewerID {
+		return true
+	}
+	if isBlockedBetween(ownerID, viewerID) {
+		return false
+	}
+	return privacyAllows(getPrivacySettings(ownerID).PresenceVisibility, ownerID, viewerID)
+}
+
+// ✅ Kullanıcıların durumunu getir (görülemeyenler atlanır)
+// Görünürlük (engel ve gizlilik ayarı) tek sorguda çözülür.
+// Bellekte hareket kaydı yoksa veritabanındaki last_seen_at kullanılır.
+func presenceFor(userIDs []uint, viewerID uint) []realtime.PresenceInfo {
+	result := []realtime.PresenceInfo{}
+	if len(userIDs) == 0 {
+		return result
+	}
+
+	var users []models.User
+	database.DB.Raw(`
+		SELECT u.id, u.last_seen_at FROM users u
+		LEFT JOIN privacy_settings ps ON ps.user_id = u.id AND ps.deleted_at IS NULL
+		WHERE u.id IN @ids AND u.deleted_at IS NULL
+			AND NOT EXISTS (
+				SELECT 1 FROM user_blocks b WHERE b.deleted_at IS NULL
+				AND ((b.blocker_id = u.id AND b.blocked_id = @viewer) OR (b.blocker_id = @viewer AND b.blocked_id = u.id))
+			)
+			AND `+privacyAllowsSQL("COALESCE(ps.presence_visibility, 'friends')", "u.id"),
+		map[string]interface{}{"ids": userIDs, "viewer": viewerID}).Scan(&users)
+
+	for _, user := range users {
+		info := realtime.PresenceInfo{
+			UserID:     user.ID,
+			Status:     realtime.Presence.Status(user.ID),
+			LastSeenAt: user.LastSeenAt,
+		}
+		if lastActivity, ok := realtime.Presence.LastActivity(user.ID); ok {
+			info.LastSeenAt = &lastActivity
+		}
+		result = append(result, info)
+	}
+	return result
+}
+
+// 🔥 Kullanıcının durumuna abone olanların izinlerini yeniden kontrol et
+// Engelleme, arkadaşlığın bitmesi veya gizlilik ayarının daraltılmasından sonra
+// artık durumu göremeyen abonelerin aboneliği kaldırılır.
+func revalidatePresenceSubscribers(ownerID uint) {
+	subscribers := realtime.Presence.Subscribers(ownerID)
+	if len(subscribers) == 0 {
+		return
+	}
+
+	var allowed []uint
+	if err := database.DB.Raw(`
+		SELECT v.id FROM users v
+		LEFT JOIN privacy_settings ps ON ps.user_id = @owner AND ps.deleted_at IS NULL
+		WHERE v.id IN @subscribers
+			AND NOT EXISTS (
+				SELECT 1 FROM user_blocks b WHERE b.deleted_at IS NULL
+				AND ((b.blocker_id = @owner AND b.blocked_id = v.id) OR (b.blocker_id = v.id AND b.blocked_id = @owner))
+			)
+			AND `+privacyAllowsViewerSQL("COALESCE(ps.presence_visibility, 'friends')", "@owner", "v.id"),
+		map[string]interface{}{"owner": ownerID, "subscribers": subscribers}).Scan(&allowed).Error; err != nil {
+		// Kontrol edilemezse gizlilik açısından güvenli taraf seçilir
+		realtime.Presence.Unsubscribe(ownerID, subscribers)
+		return
+	}
+
+	keep := make(map[uint]bool, len(allowed))
+	for _, id := range allowed {
+		keep[id] = true
+	}
+	var revoked []uint
+	for _, subscriberID := range subscribers {
+		if !keep[subscriberID] {
+			revoked = append(revoked, subscriberID)
+		}
+	}
+	if len(revoked) > 0 {
+		realtime.Presence.Unsubscribe(ownerID, revoked)
+	}
+}
+
+// 🔥 Çevrimiçi Durumları Getir (GET /presence?user_ids=1,2,3)
+func GetPresence(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	var userIDs []uint
+	for _, raw := range strings.Split(c.Query("user_ids"), ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz kullanıcı ID"})
+			return
+		}
+		userIDs = append(userIDs, uint(id))
+	}
+
+	if len(userIDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "En az bir kullanıcı ID belirtilmelidir"})
+		return
+	}
+	if len(userIDs) > maxPresenceQuery {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Tek seferde en fazla 100 kullanıcı sorgulanabilir"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": presenceFor(userIDs, userID.(uint))})
+}
+
+// ✅ Çevrimiçi durum route'larını kaydet
+func RegisterPresenceRoutes(router *gin.Engine) {
+	router.GET("/presence", AuthMiddleware(), GetPresence)
+}
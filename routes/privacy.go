@@ -0,0 +1,156 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"arcurachat_api/database"
+	"arcurachat_api/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ✅ Kullanıcının gizlilik ayarlarını getir (kayıt yoksa varsayılanlar)
+func getPrivacySettings(userID uint) models.PrivacySettings {
+	var settings models.PrivacySettings
+	if err := database.DB.Where("user_id = ?", userID).First(&settings).Error; err != nil {
+		return models.DefaultPrivacySettings(userID)
+	}
+	return settings
+}
+
+// ✅ İki kullanıcı arkadaş mı?
+func areFriends(userID, otherUserID uint) bool {
+	var count int64
+	database.DB.Model(&models.Friendship{}).
+		Where("user_id = ? AND friend_id = ?", userID, otherUserID).
+		Count(&count)
+	return count > 0
+}
+
+// ✅ İki kullanıcının ortak arkadaşı var mı?
+func haveMutualFriend(userID, otherUserID uint) bool {
+	var count int64
+	database.DB.Table("friendships f1").
+		Joins("JOIN friendships f2 ON f2.user_id = f1.friend_id AND f2.deleted_at IS NULL").
+		Where("f1.user_id = ? AND f2.friend_id = ? AND f1.deleted_at IS NULL", userID, otherUserID).
+		Count(&count)
+	return count > 0
+}
+
+// 🔥 Gizlilik seviyesi izleyicinin erişimine izin veriyor mu?
+func privacyAllows(level string, ownerID, viewerID uint) bool {
+	if ownerID == viewerID {
+		return true
+	}
+
+	switch level {
+	case models.PrivacyEveryone:
+		return true
+	case models.PrivacyFriends:
+		return areFriends(ownerID, viewerID)
+	case models.PrivacyFriendsOfFriends:
+		return areFriends(ownerID, viewerID) || haveMutualFriend(ownerID, viewerID)
+	default:
+		return false
+	}
+}
+
+// ✅ Gizlilik seviyesinin SQL karşılığı
+// levelExpr seviye sütunu, ownerExpr sahibin ID sütunudur; izleyici @viewer parametresidir.
+func privacyAllowsSQL(levelExpr, ownerExpr string) string {
+	return privacyAllowsViewerSQL(levelExpr, ownerExpr, "@viewer")
+}
+
+// ✅ İzleyicisi bir sütun olan gizlilik kontrolü (birden fazla izleyiciyi tek sorguda kontrol etmek için)
+func privacyAllowsViewerSQL(levelExpr, ownerExpr, viewerExpr string) string {
+	return fmt.Sprintf(`(%[2]s = %[3]s OR %[1]s = 'everyone'
+		OR (%[1]s IN ('friends', 'friends_of_friends') AND EXISTS (
+			SELECT 1 FROM friendships pf WHERE pf.user_id = %[3]s AND pf.friend_id = %[2]s AND pf.deleted_at IS NULL
+		))
+		OR (%[1]s = 'friends_of_friends' AND EXISTS (
+			SELECT 1 FROM friendships pf1
+			JOIN friendships pf2 ON pf2.user_id = pf1.friend_id AND pf2.deleted_at IS NULL
+			WHERE pf1.user_id = %[3]s AND pf1.deleted_at IS NULL AND pf2.friend_id = %[2]s
+		)))`, levelExpr, ownerExpr, viewerExpr)
+}
+
+// ✅ Gizlilik Ayarlarını Getir (GET /users/:id/privacy)
+func GetPrivacySettings(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	// Kullanıcı sadece kendi ayarlarını görüntüleyebilir
+	if c.Param("id") != strconv.Itoa(int(userID.(uint))) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Bu işlemi yapmaya yetkiniz yok"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": getPrivacySettings(userID.(uint))})
+}
+
+// 🔥 Gizlilik Ayarlarını Güncelle (PUT /users/:id/privacy)
+func UpdatePrivacySettings(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	// Kullanıcı sadece kendi ayarlarını değiştirebilir
+	if c.Param("id") != strconv.Itoa(int(userID.(uint))) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Bu işlemi yapmaya yetkiniz yok"})
+		return
+	}
+
+	var input struct {
+		EmailVisibility    string `json:"email_visibility"`
+		PhoneVisibility    string `json:"phone_visibility"`
+		FriendRequests     string `json:"friend_requests"`
+		GroupAdds          string `json:"group_adds"`
+		PresenceVisibility string `json:"presence_visibility"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz veri"})
+		return
+	}
+
+	settings := getPrivacySettings(userID.(uint))
+	previousPresence := settings.PresenceVisibility
+	fields := []struct {
+		value  string
+		target *string
+	}{
+		{input.EmailVisibility, &settings.EmailVisibility},
+		{input.PhoneVisibility, &settings.PhoneVisibility},
+		{input.FriendRequests, &settings.FriendRequests},
+		{input.GroupAdds, &settings.GroupAdds},
+		{input.PresenceVisibility, &settings.PresenceVisibility},
+	}
+	for _, field := range fields {
+		if field.value == "" {
+			continue
+		}
+		if !models.IsValidPrivacyLevel(field.value) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Gizlilik seviyesi everyone, friends, friends_of_friends veya nobody olmalıdır"})
+			return
+		}
+		*field.target = field.value
+	}
+
+	// Kayıt yoksa varsayılanların üzerine oluşturulur
+	if err := database.DB.Save(&settings).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gizlilik ayarları güncellenemedi"})
+		return
+	}
+	if settings.PresenceVisibility != previousPresence {
+		revalidatePresenceSubscribers(userID.(uint))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Gizlilik ayarları güncellendi", "data": settings})
+}
@@ -0,0 +1,205 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"unicode"
+	"unicode/utf8"
+
+	"arcurachat_api/database"
+	"arcurachat_api/models"
+	"arcurachat_api/realtime"
+	"arcurachat_api/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ✅ Tepki olay tipi
+const EventReaction = "message.reaction"
+
+// Bir mesajda bulunabilecek en fazla farklı emoji sayısı
+var maxDistinctReactions = utils.GetEnvInt("MAX_DISTINCT_REACTIONS", 20)
+
+var errReactionLimit = errors.New("farklı tepki sınırına ulaşıldı")
+
+// ✅ Geçerli bir emoji mi?
+// Ten rengi, ZWJ dizileri ve bayraklar birden fazla kod noktasından oluşabilir.
+func isValidEmoji(emoji string) bool {
+	if emoji == "" || len(emoji) > 64 || utf8.RuneCountInString(emoji) > 16 {
+		return false
+	}
+
+	hasSymbol := false
+	for _, r := range emoji {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsSpace(r) || unicode.IsControl(r) {
+			return false
+		}
+		if r >= 0x2000 {
+			hasSymbol = true
+		}
+	}
+	return hasSymbol
+}
+
+// ✅ Mesajlara tepki özetlerini ekle
+func attachReactions(messages []models.Message, userID uint) {
+	if len(messages) == 0 {
+		return
+	}
+
+	ids := make([]uint, 0, len(messages))
+	byID := make(map[uint]int, len(messages))
+	for i, message := range messages {
+		ids = append(ids, message.ID)
+		byID[message.ID] = i
+	}
+
+	var rows []struct {
+		MessageID   uint
+		Emoji       string
+		Count       int64
+		ReactedByMe bool
+	}
+	database.DB.Model(&models.MessageReaction{}).
+		Select("message_id, emoji, COUNT(*) AS count, BOOL_OR(user_id = ?) AS reacted_by_me", userID).
+		Where("message_id IN ?", ids).
+		Group("message_id, emoji").
+		Order("MIN(created_at) ASC").
+		Scan(&rows)
+
+	for _, row := range rows {
+		i := byID[row.MessageID]
+		messages[i].Reactions = append(messages[i].Reactions, models.ReactionSummary{
+			Emoji:       row.Emoji,
+			Count:       row.Count,
+			ReactedByMe: row.ReactedByMe,
+		})
+	}
+}
+
+// ✅ Tepki değişikliğini konuşmanın katılımcılarına yayınla
+func broadcastReaction(message models.Message, userID uint, emoji, action string) {
+	realtime.DefaultHub.SendToUsers(activeParticipantIDs(message.ConversationID), realtime.Event{
+		Type: EventReaction,
+		Data: gin.H{
+			"conversation_id": message.ConversationID,
+			"message_id":      message.ID,
+			"user_id":         userID,
+			"emoji":           emoji,
+			"action":          action,
+		},
+	})
+}
+
+// ✅ URL'deki mesajı getir ve görülebilirliğini kontrol et
+func reactableMessage(c *gin.Context, userID uint) (models.Message, bool) {
+	var message models.Message
+	if err := database.DB.First(&message, c.Param("message_id")).Error; err != nil || !canViewMessage(message, userID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Mesaj bulunamadı"})
+		return message, false
+	}
+	if message.DeletedForEveryoneAt != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Silinmiş mesaja tepki verilemez"})
+		return message, false
+	}
+
+	// 🔥 Engellenen kullanıcının birebir mesajlarına tepki verilemez
+	if isDirectConversationBlocked(message.ConversationID, userID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Bu mesaja tepki veremezsiniz"})
+		return message, false
+	}
+	return message, true
+}
+
+// 🔥 Tepki Ekle (POST /messages/:message_id/reactions)
+func AddReaction(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	var input struct {
+		Emoji string `json:"emoji" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil || !isValidEmoji(input.Emoji) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz emoji"})
+		return
+	}
+
+	message, ok := reactableMessage(c, userID.(uint))
+	if !ok {
+		return
+	}
+
+	created := false
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// Aynı mesaja eşzamanlı tepkilerde sınır kontrolü için mesaj satırı kilitlenir
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&models.Message{}, message.ID).Error; err != nil {
+			return err
+		}
+
+		var used int64
+		tx.Model(&models.MessageReaction{}).Where("message_id = ? AND emoji = ?", message.ID, input.Emoji).Count(&used)
+		if used == 0 {
+			var distinct int64
+			tx.Model(&models.MessageReaction{}).Where("message_id = ?", message.ID).Distinct("emoji").Count(&distinct)
+			if distinct >= int64(maxDistinctReactions) {
+				return errReactionLimit
+			}
+		}
+
+		reaction := models.MessageReaction{MessageID: message.ID, UserID: userID.(uint), Emoji: input.Emoji}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&reaction)
+		created = result.RowsAffected > 0
+		return result.Error
+	})
+	if errors.Is(err, errReactionLimit) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Bir mesajda en fazla %d farklı tepki olabilir", maxDistinctReactions)})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Tepki eklenemedi"})
+		return
+	}
+
+	if created {
+		broadcastReaction(message, userID.(uint), input.Emoji, "added")
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tepki eklendi"})
+}
+
+// 🔥 Tepkiyi Kaldır (DELETE /messages/:message_id/reactions/:emoji)
+func RemoveReaction(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	message, ok := reactableMessage(c, userID.(uint))
+	if !ok {
+		return
+	}
+
+	emoji := c.Param("emoji")
+	result := database.DB.Unscoped().
+		Where("message_id = ? AND user_id = ? AND emoji = ?", message.ID, userID, emoji).
+		Delete(&models.MessageReaction{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Tepki kaldırılamadı"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tepki bulunamadı"})
+		return
+	}
+
+	broadcastReaction(message, userID.(uint), emoji, "removed")
+	c.JSON(http.StatusOK, gin.H{"message": "Tepki kaldırıldı"})
+}
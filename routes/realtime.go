@@ -0,0 +1,127 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"arcurachat_api/realtime"
+	"arcurachat_api/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	// Mobil ve web istemciler farklı origin'lerden bağlanır; kimlik doğrulama token ile yapılır
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// ✅ İstemciden gelen WebSocket mesajı
+type clientMessage struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// 🔥 İstemci mesajlarını işle
+func handleClientMessage(client *realtime.Client, raw []byte) {
+	var message clientMessage
+	if err := json.Unmarshal(raw, &message); err != nil {
+		client.Send(realtime.Event{Type: "error", Data: gin.H{"error": "Geçersiz mesaj"}})
+		return
+	}
+
+	switch message.Type {
+	case "ping":
+		client.Send(realtime.Event{Type: "pong"})
+	case "presence.subscribe":
+		var data struct {
+			UserIDs []uint `json:"user_ids"`
+		}
+		if err := json.Unmarshal(message.Data, &data); err != nil || len(data.UserIDs) > maxPresenceQuery {
+			client.Send(realtime.Event{Type: "error", Data: gin.H{"error": "Geçersiz abonelik isteği"}})
+			return
+		}
+
+		// 🔥 Sadece durumunu görmeye izinli olunan kullanıcılara abone olunur
+		presence := presenceFor(data.UserIDs, client.UserID)
+		allowed := make([]uint, 0, len(presence))
+		for _, info := range presence {
+			allowed = append(allowed, info.UserID)
+		}
+		realtime.Presence.Subscribe(client.UserID, allowed)
+		client.Send(realtime.Event{Type: realtime.EventPresence, Data: presence})
+	case realtime.EventSignal:
+		var data struct {
+			ConversationID uint   `json:"conversation_id"`
+			Type           string `json:"type"`
+		}
+		if err := json.Unmarshal(message.Data, &data); err != nil {
+			client.Send(realtime.Event{Type: "error", Data: gin.H{"error": "Geçersiz sinyal"}})
+			return
+		}
+		if status, reason := publishSignal(data.ConversationID, client.UserID, data.Type); status != 0 {
+			client.Send(realtime.Event{Type: "error", Data: gin.H{"error": reason}})
+		}
+	default:
+		client.Send(realtime.Event{Type: "error", Data: gin.H{"error": "Bilinmeyen mesaj tipi"}})
+	}
+}
+
+// 🔥 WebSocket Bağlantı Bileti (POST /ws/ticket)
+// Bilet tek kullanımlıktır ve kısa sürede geçersiz olur.
+func IssueWebSocketTicket(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	ticket, expiresAt, err := realtime.Tickets.Issue(userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Bilet oluşturulamadı"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"ticket": ticket, "expires_at": expiresAt})
+}
+
+// 🔥 WebSocket Bağlantısı (GET /ws)
+// Header gönderebilen istemciler Authorization ile, tarayıcılar ise POST /ws/ticket ile alınan
+// tek kullanımlık "ticket" parametresiyle bağlanır. JWT sorgu parametresi olarak kabul edilmez.
+func ServeWebSocket(c *gin.Context) {
+	var userID uint
+	if authHeader := c.GetHeader("Authorization"); strings.HasPrefix(authHeader, "Bearer ") {
+		id, err := utils.ValidateToken(authHeader[7:])
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Geçersiz token"})
+			return
+		}
+		userID = id
+	} else if value := c.Query("ticket"); value != "" {
+		id, ok := realtime.Tickets.Redeem(value)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Geçersiz veya süresi dolmuş bilet"})
+			return
+		}
+		userID = id
+	} else {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token veya bilet gerekli"})
+		return
+	}
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+
+	realtime.DefaultHub.Serve(conn, userID, handleClientMessage)
+}
+
+// ✅ Gerçek zamanlı route'ları kaydet
+func RegisterRealtimeRoutes(router *gin.Engine) {
+	router.POST("/ws/ticket", AuthMiddleware(), IssueWebSocketTicket)
+	router.GET("/ws", ServeWebSocket)
+}
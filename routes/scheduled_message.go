@@ -0,0 +1,362 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"arcurachat_api/database"
+	"arcurachat_api/models"
+	"arcurachat_api/realtime"
+	"arcurachat_api/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ✅ Zamanlanmış mesaj durum değişikliği olay tipi
+const EventScheduledMessage = "scheduled_message.updated"
+
+var (
+	// Kullanıcı başına bekleyen en fazla zamanlanmış mesaj
+	maxScheduledMessages = utils.GetEnvInt("MAX_SCHEDULED_MESSAGES", 100)
+	// Mesajın en fazla ne kadar ileriye zamanlanabileceği
+	maxScheduleAhead = utils.GetEnvDuration("SCHEDULED_MESSAGE_MAX_AHEAD", 365*24*time.Hour)
+	// Zamanlayıcının zamanı gelen mesajları kontrol etme aralığı
+	schedulerInterval = utils.GetEnvDuration("SCHEDULER_INTERVAL", 5*time.Second)
+	// Geçici hatalarda bir mesajın en fazla kaç kez deneneceği
+	schedulerMaxAttempts = utils.GetEnvInt("SCHEDULER_MAX_ATTEMPTS", 10)
+	// İlk tekrar denemeden önceki bekleme; her denemede iki katına çıkar
+	schedulerRetryDelay = utils.GetEnvDuration("SCHEDULER_RETRY_DELAY", 30*time.Second)
+	// Tekrar denemeler arasındaki en uzun bekleme
+	schedulerMaxRetryDelay = utils.GetEnvDuration("SCHEDULER_MAX_RETRY_DELAY", time.Hour)
+)
+
+// ✅ Zamanlama tarihi geçerli mi?
+func scheduleTimeError(scheduledAt time.Time) string {
+	if !scheduledAt.After(time.Now()) {
+		return "Gönderim zamanı gelecekte olmalıdır"
+	}
+	if scheduledAt.After(time.Now().Add(maxScheduleAhead)) {
+		return "Mesaj bu kadar ileriye zamanlanamaz"
+	}
+	return ""
+}
+
+var errScheduledNotPending = errors.New("zamanlanmış mesaj beklemede değil")
+
+// ✅ Kullanıcının bekleyen zamanlanmış mesajını kilitleyerek getir
+// Zamanlayıcı mesajı o anda gönderiyorsa gönderim bitene kadar beklenir.
+func lockScheduledMessage(tx *gorm.DB, scheduledID string, userID uint) (models.ScheduledMessage, error) {
+	var scheduled models.ScheduledMessage
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND user_id = ?", scheduledID, userID).
+		First(&scheduled).Error; err != nil {
+		return scheduled, err
+	}
+	if scheduled.Status != models.ScheduledStatusPending {
+		return scheduled, errScheduledNotPending
+	}
+	return scheduled, nil
+}
+
+// ✅ Zamanlanmış mesaj işlem hatasını yanıtla
+func respondScheduledError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Zamanlanmış mesaj bulunamadı"})
+	case errors.Is(err, errScheduledNotPending):
+		c.JSON(http.StatusConflict, gin.H{"error": "Bu mesaj artık beklemede değil"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+// 🔥 Mesajı Zamanla (POST /messages/scheduled)
+// Yetkiler şimdi ve gönderim anında ayrıca kontrol edilir.
+func ScheduleMessage(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	var input struct {
+		ConversationID uint      `json:"conversation_id" binding:"required"`
+		Content        string    `json:"content" binding:"required"`
+		ReplyToID      *uint     `json:"reply_to_id"`
+		ThreadRootID   *uint     `json:"thread_root_id"`
+		ScheduledAt    time.Time `json:"scheduled_at" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil || strings.TrimSpace(input.Content) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz veri"})
+		return
+	}
+	if reason := scheduleTimeError(input.ScheduledAt); reason != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": reason})
+		return
+	}
+
+	if status, reason := conversationWriteRestriction(input.ConversationID, userID.(uint)); status != 0 {
+		c.JSON(status, gin.H{"error": reason})
+		return
+	}
+	if _, status, reason := validateReplyTargets(input.ConversationID, userID.(uint), input.ReplyToID, input.ThreadRootID); status != 0 {
+		c.JSON(status, gin.H{"error": reason})
+		return
+	}
+
+	var pending int64
+	database.DB.Model(&models.ScheduledMessage{}).
+		Where("user_id = ? AND status = ?", userID, models.ScheduledStatusPending).
+		Count(&pending)
+	if pending >= int64(maxScheduledMessages) {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("En fazla %d mesaj zamanlanabilir", maxScheduledMessages)})
+		return
+	}
+
+	scheduled := models.ScheduledMessage{
+		UserID:         userID.(uint),
+		ConversationID: input.ConversationID,
+		Content:        input.Content,
+		ReplyToID:      input.ReplyToID,
+		ThreadRootID:   input.ThreadRootID,
+		ScheduledAt:    input.ScheduledAt,
+		Status:         models.ScheduledStatusPending,
+	}
+	if err := database.DB.Create(&scheduled).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Mesaj zamanlanamadı"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Mesaj zamanlandı", "data": scheduled})
+}
+
+// ✅ Zamanlanmış Mesajlarım (GET /messages/scheduled?status=pending&conversation_id=)
+func GetScheduledMessages(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	query := database.DB.Where("user_id = ?", userID)
+	if status := c.DefaultQuery("status", models.ScheduledStatusPending); status != "all" {
+		query = query.Where("status = ?", status)
+	}
+	if conversationID := c.Query("conversation_id"); conversationID != "" {
+		query = query.Where("conversation_id = ?", conversationID)
+	}
+
+	page, limit := getPagination(c)
+	var scheduled []models.ScheduledMessage
+	if err := query.Order("scheduled_at ASC").
+		Offset((page - 1) * limit).Limit(limit).
+		Find(&scheduled).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Zamanlanmış mesajlar alınamadı"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": scheduled, "page": page, "limit": limit})
+}
+
+// 🔥 Zamanlanmış Mesajı Düzenle (PUT /messages/scheduled/:scheduled_id)
+// Sadece bekleyen mesajlar düzenlenebilir.
+func UpdateScheduledMessage(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	var input struct {
+		Content     *string    `json:"content"`
+		ScheduledAt *time.Time `json:"scheduled_at"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz veri"})
+		return
+	}
+
+	updates := map[string]interface{}{}
+	if input.Content != nil {
+		if strings.TrimSpace(*input.Content) == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Mesaj içeriği boş olamaz"})
+			return
+		}
+		updates["content"] = *input.Content
+	}
+	if input.ScheduledAt != nil {
+		if reason := scheduleTimeError(*input.ScheduledAt); reason != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": reason})
+			return
+		}
+		updates["scheduled_at"] = *input.ScheduledAt
+		// Yeni zamanda deneme sayacı baştan başlar
+		updates["attempts"] = 0
+		updates["next_attempt_at"] = nil
+	}
+
+	var scheduled models.ScheduledMessage
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if scheduled, err = lockScheduledMessage(tx, c.Param("scheduled_id"), userID.(uint)); err != nil {
+			return err
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+		return tx.Model(&scheduled).Updates(updates).Error
+	})
+	if err != nil {
+		respondScheduledError(c, err, "Zamanlanmış mesaj güncellenemedi")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Zamanlanmış mesaj güncellendi", "data": scheduled})
+}
+
+// ✅ Zamanlanmış Mesajı İptal Et (DELETE /messages/scheduled/:scheduled_id)
+func CancelScheduledMessage(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	var scheduled models.ScheduledMessage
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if scheduled, err = lockScheduledMessage(tx, c.Param("scheduled_id"), userID.(uint)); err != nil {
+			return err
+		}
+		return tx.Model(&scheduled).Update("status", models.ScheduledStatusCanceled).Error
+	})
+	if err != nil {
+		respondScheduledError(c, err, "Zamanlanmış mesaj iptal edilemedi")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Zamanlanmış mesaj iptal edildi", "data": scheduled})
+}
+
+// 🔥 Zamanlanmış mesaj gönderici
+// Birden fazla API sunucusu çalışırken aynı mesajın iki kez gönderilmemesi için satırlar
+// FOR UPDATE SKIP LOCKED ile alınır; mesaj kaydı ve durum güncellemesi aynı işlemde yapılır.
+// Her mesaj kendi işleminde gönderilir; böylece aynı kullanıcının art arda gelen mesajları
+// yavaş mod kontrolünde bir öncekini görür.
+func RunMessageScheduler() {
+	ticker := time.NewTicker(schedulerInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		for dispatchNextScheduledMessage() {
+			// Birikmiş mesajlar varsa bir sonraki turu beklemeden devam et
+		}
+	}
+}
+
+// ✅ Başarısız denemeden sonra beklenecek süre (üstel artış)
+func scheduledRetryDelay(attempts int) time.Duration {
+	delay := schedulerRetryDelay
+	if delay < time.Second {
+		delay = time.Second
+	}
+	for i := 1; i < attempts && delay < schedulerMaxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > schedulerMaxRetryDelay {
+		delay = schedulerMaxRetryDelay
+	}
+	return delay
+}
+
+// ✅ Zamanı gelen ilk mesajı gönder, işlenecek mesaj yoksa false döndür
+// Tekrar denenen mesajlar bir sonraki deneme zamanına göre sıralanır ve yeni mesajların önünü tıkamaz.
+func dispatchNextScheduledMessage() bool {
+	var (
+		claimed      bool
+		finished     bool
+		scheduled    models.ScheduledMessage
+		message      models.Message
+		mentionedIDs []uint
+	)
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND scheduled_at <= ? AND (next_attempt_at IS NULL OR next_attempt_at <= ?)", models.ScheduledStatusPending, now, now).
+			Order("COALESCE(next_attempt_at, scheduled_at) ASC, id ASC").
+			First(&scheduled).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		claimed = true
+
+		input := sendMessageInput{
+			ConversationID: scheduled.ConversationID,
+			Content:        scheduled.Content,
+			ReplyToID:      scheduled.ReplyToID,
+			ThreadRootID:   scheduled.ThreadRootID,
+		}
+
+		// Yetkiler gönderim anında yeniden kontrol edilir (gruptan ayrılma, engelleme, susturma...)
+		var status int
+		var reason string
+		message, mentionedIDs, status, reason = sendMessage(tx, scheduled.UserID, input)
+		scheduled.Attempts++
+		switch {
+		case status == 0:
+			scheduled.Status = models.ScheduledStatusSent
+			scheduled.MessageID = &message.ID
+			scheduled.SentAt = &now
+			scheduled.NextAttemptAt = nil
+			scheduled.FailureReason = ""
+			finished = true
+		case (status == http.StatusTooManyRequests || status >= http.StatusInternalServerError) && scheduled.Attempts < schedulerMaxAttempts:
+			// Yavaş mod veya geçici hata: bekleme süresi sonunda tekrar denenir
+			next := now.Add(scheduledRetryDelay(scheduled.Attempts))
+			scheduled.NextAttemptAt = &next
+			scheduled.FailureReason = reason
+		default:
+			scheduled.Status = models.ScheduledStatusFailed
+			scheduled.FailureReason = reason
+			scheduled.NextAttemptAt = nil
+			finished = true
+		}
+
+		return tx.Model(&scheduled).Updates(map[string]interface{}{
+			"status":          scheduled.Status,
+			"message_id":      scheduled.MessageID,
+			"sent_at":         scheduled.SentAt,
+			"failure_reason":  scheduled.FailureReason,
+			"attempts":        scheduled.Attempts,
+			"next_attempt_at": scheduled.NextAttemptAt,
+		}).Error
+	})
+	if err != nil {
+		log.Printf("Hata: Zamanlanmış mesaj gönderilemedi - %v", err)
+		return false
+	}
+	if !claimed {
+		return false
+	}
+
+	if scheduled.Status == models.ScheduledStatusSent {
+		publishMessage(message, mentionedIDs)
+	}
+	if finished {
+		realtime.DefaultHub.SendToUser(scheduled.UserID, realtime.Event{
+			Type: EventScheduledMessage,
+			Data: gin.H{"scheduled_message": scheduled},
+		})
+	}
+	return true
+}
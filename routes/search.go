@@ -20,6 +20,12 @@ func sanitizeQuery(query string) string {
 
 // ✅ Kullanıcıları Arama (SQL Injection'a karşı güvenli)
 func SearchUsers(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
 	query := c.Query("query")
 	if query == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Arama terimi belirtilmelidir"})
@@ -28,8 +34,30 @@ func SearchUsers(c *gin.Context) {
 
 	query = sanitizeQuery(query) // 🔥 Kullanıcı girdisini temizle
 
-	var users []models.User
-	if err := database.DB.Where("username LIKE ? ESCAPE '\\' OR email LIKE ? ESCAPE '\\'", "%"+query+"%", "%"+query+"%").Find(&users).Error; err != nil {
+	// 🔥 E-posta ve telefon sadece gizlilik ayarı izin veriyorsa döner,
+	// e-posta ile arama da yalnızca e-postası görünür kullanıcılarda yapılır
+	emailVisible := privacyAllowsSQL("COALESCE(ps.email_visibility, 'friends')", "users.id")
+	phoneVisible := privacyAllowsSQL("COALESCE(ps.phone_visibility, 'friends')", "users.id")
+
+	var users []struct {
+		ID          uint    `json:"id"`
+		Username    string  `json:"username"`
+		FirstName   string  `json:"first_name"`
+		LastName    string  `json:"last_name"`
+		Email       *string `json:"email,omitempty"`
+		PhoneNumber *string `json:"phone_number,omitempty"`
+	}
+	if err := database.DB.Raw(`
+		SELECT users.id, users.username, users.first_name, users.last_name,
+			CASE WHEN `+emailVisible+` THEN users.email END AS email,
+			CASE WHEN `+phoneVisible+` THEN users.phone_number END AS phone_number
+		FROM users
+		LEFT JOIN privacy_settings ps ON ps.user_id = users.id AND ps.deleted_at IS NULL
+		WHERE users.deleted_at IS NULL
+			AND (users.username LIKE @query ESCAPE '\' OR (users.email LIKE @query ESCAPE '\' AND `+emailVisible+`))
+			AND users.id NOT IN (SELECT blocked_id FROM user_blocks WHERE blocker_id = @viewer AND deleted_at IS NULL)
+			AND users.id NOT IN (SELECT blocker_id FROM user_blocks WHERE blocked_id = @viewer AND deleted_at IS NULL)`,
+		map[string]interface{}{"viewer": userID.(uint), "query": "%" + query + "%"}).Scan(&users).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Kullanıcılar aranırken hata oluştu"})
 		return
 	}
@@ -58,6 +86,12 @@ func SearchGroups(c *gin.Context) {
 
 // ✅ Mesajları Arama (SQL Injection'a karşı güvenli)
 func SearchMessages(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
 	query := c.Query("query")
 	if query == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Arama terimi belirtilmelidir"})
@@ -67,7 +101,7 @@ func SearchMessages(c *gin.Context) {
 	query = sanitizeQuery(query) // 🔥 Kullanıcı girdisini temizle
 
 	var messages []models.Message
-	if err := database.DB.Where("content LIKE ? ESCAPE '\\'", "%"+query+"%").Find(&messages).Error; err != nil {
+	if err := database.DB.Scopes(accessibleMessages(userID.(uint))).Where("content LIKE ? ESCAPE '\\'", "%"+query+"%").Find(&messages).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Mesajlar aranırken hata oluştu"})
 		return
 	}
@@ -78,7 +112,7 @@ func SearchMessages(c *gin.Context) {
 // ✅ Arama route'larını kaydet
 func RegisterSearchRoutes(router *gin.Engine) {
 	searchRoutes := router.Group("/search")
-	searchRoutes.GET("/users", SearchUsers)
+	searchRoutes.GET("/users", AuthMiddleware(), SearchUsers) // 🔥 Engellenen kullanıcılar sonuçlarda görünmez
 	searchRoutes.GET("/groups", SearchGroups)
-	searchRoutes.GET("/messages", SearchMessages)
+	searchRoutes.GET("/messages", AuthMiddleware(), SearchMessages) // 🔥 Sadece erişilebilen konuşmalarda arar
 }
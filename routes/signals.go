@@ -0,0 +1,129 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"arcurachat_api/database"
+	"arcurachat_api/models"
+	"arcurachat_api/realtime"
+	"arcurachat_api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ✅ Kullanıcı başına sinyal sınırı (tüm konuşmalar için toplam)
+var signalLimiter = utils.NewRateLimiter(
+	utils.GetEnvInt("SIGNAL_RATE_LIMIT", 60),
+	utils.GetEnvDuration("SIGNAL_RATE_WINDOW", time.Minute),
+)
+
+// ✅ Konuşmanın aktif katılımcıları
+func activeParticipantIDs(conversationID uint) []uint {
+	var userIDs []uint
+	database.DB.Model(&models.ConversationParticipant{}).
+		Where("conversation_id = ? AND left_at IS NULL", conversationID).
+		Pluck("user_id", &userIDs)
+	return userIDs
+}
+
+// ✅ Sinyali gönderen hariç katılımcılara yayınla
+func broadcastSignal(signal realtime.Signal) {
+	var recipients []uint
+	for _, id := range activeParticipantIDs(signal.ConversationID) {
+		if id != signal.UserID {
+			recipients = append(recipients, id)
+		}
+	}
+	realtime.DefaultHub.SendToUsers(recipients, realtime.Event{Type: realtime.EventSignal, Data: signal})
+}
+
+// 🔥 Sinyali kaydet ve gerekiyorsa yayınla
+func publishSignal(conversationID, userID uint, signalType string) (int, string) {
+	if !realtime.IsValidSignal(signalType) {
+		return http.StatusBadRequest, "Sinyal tipi typing, recording_audio veya stop olmalıdır"
+	}
+
+	if allowed, retryAfter := signalLimiter.Allow(strconv.Itoa(int(userID))); !allowed {
+		return http.StatusTooManyRequests, fmt.Sprintf("Çok fazla sinyal gönderildi, %d saniye sonra tekrar deneyin", int(retryAfter.Seconds())+1)
+	}
+
+	if status, reason := conversationWriteRestriction(conversationID, userID); status != 0 {
+		return status, reason
+	}
+
+	if signal, changed := realtime.Signals.Set(conversationID, userID, signalType); changed {
+		broadcastSignal(signal)
+	}
+	return 0, ""
+}
+
+// ✅ Mesaj gönderildiğinde kullanıcının sinyalini kapat
+func clearSignal(conversationID, userID uint) {
+	if signal, changed := realtime.Signals.Set(conversationID, userID, realtime.SignalStop); changed {
+		broadcastSignal(signal)
+	}
+}
+
+// 🔥 Sinyal Gönder (POST /conversations/:id/signals)
+// WebSocket kullanmayan istemciler içindir; WebSocket üzerinden "conversation.signal" mesajı da kullanılabilir.
+func SendConversationSignal(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	conversationID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz konuşma ID"})
+		return
+	}
+
+	var input struct {
+		Type string `json:"type" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz veri"})
+		return
+	}
+
+	if status, reason := publishSignal(uint(conversationID), userID.(uint), input.Type); status != 0 {
+		c.JSON(status, gin.H{"error": reason})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Sinyal gönderildi"})
+}
+
+// ✅ Aktif Sinyalleri Getir (GET /conversations/:id/signals)
+func GetConversationSignals(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	conversationID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz konuşma ID"})
+		return
+	}
+
+	if !isActiveParticipant(uint(conversationID), userID.(uint)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Bu konuşmaya erişim yetkiniz yok"})
+		return
+	}
+
+	signals := []realtime.Signal{}
+	for _, signal := range realtime.Signals.Active(uint(conversationID)) {
+		if signal.UserID != userID.(uint) {
+			signals = append(signals, signal)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": signals})
+}
@@ -0,0 +1,134 @@
+package routes
+
+import (
+	"net/http"
+
+	"arcurachat_api/database"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ✅ Önerideki ortak arkadaş önizlemesi
+type mutualFriendPreview struct {
+	CandidateID uint   `json:"-"`
+	ID          uint   `json:"id"`
+	Username    string `json:"username"`
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name"`
+}
+
+// ✅ Arkadaş önerisi
+type friendSuggestion struct {
+	ID               uint                  `json:"id"`
+	Username         string                `json:"username"`
+	FirstName        string                `json:"first_name"`
+	LastName         string                `json:"last_name"`
+	MutualCount      int64                 `json:"mutual_friend_count"`
+	SharedGroupCount int64                 `json:"shared_group_count"`
+	MutualFriends    []mutualFriendPreview `gorm:"-" json:"mutual_friends"`
+}
+
+// 🔥 Arkadaş Önerileri (GET /friends/suggestions)
+// Ortak arkadaş ve ortak grup sayısına göre sıralanır; arkadaşlar, engellenenler
+// ve bekleyen isteği olan kullanıcılar hariç tutulur.
+func GetFriendSuggestions(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	expireFriendRequests(userID.(uint))
+
+	page, limit := getPagination(c)
+	params := map[string]interface{}{
+		"user":   userID.(uint),
+		"limit":  limit,
+		"offset": (page - 1) * limit,
+	}
+
+	var suggestions []friendSuggestion
+	err := database.DB.Raw(`
+		WITH my_friends AS (
+			SELECT friend_id FROM friendships WHERE user_id = @user AND deleted_at IS NULL
+		), mutual AS (
+			SELECT f.friend_id AS candidate_id, COUNT(*) AS mutual_count
+			FROM friendships f
+			JOIN my_friends mf ON mf.friend_id = f.user_id
+			WHERE f.deleted_at IS NULL
+			GROUP BY f.friend_id
+		), shared AS (
+			SELECT gm2.user_id AS candidate_id, COUNT(DISTINCT gm2.group_id) AS shared_group_count
+			FROM group_members gm1
+			JOIN groups g ON g.id = gm1.group_id AND g.deleted_at IS NULL AND g.type <> 'channel'
+			JOIN group_members gm2 ON gm2.group_id = gm1.group_id AND gm2.deleted_at IS NULL
+			WHERE gm1.user_id = @user AND gm1.deleted_at IS NULL
+			GROUP BY gm2.user_id
+		), candidates AS (
+			SELECT COALESCE(m.candidate_id, s.candidate_id) AS candidate_id,
+				COALESCE(m.mutual_count, 0) AS mutual_count,
+				COALESCE(s.shared_group_count, 0) AS shared_group_count
+			FROM mutual m
+			FULL OUTER JOIN shared s ON s.candidate_id = m.candidate_id
+		)
+		SELECT u.id, u.username, u.first_name, u.last_name, c.mutual_count, c.shared_group_count
+		FROM candidates c
+		JOIN users u ON u.id = c.candidate_id AND u.deleted_at IS NULL
+		WHERE c.candidate_id <> @user
+			AND c.candidate_id NOT IN (SELECT friend_id FROM my_friends)
+			AND NOT EXISTS (
+				SELECT 1 FROM user_blocks b WHERE b.deleted_at IS NULL
+				AND ((b.blocker_id = @user AND b.blocked_id = c.candidate_id) OR (b.blocker_id = c.candidate_id AND b.blocked_id = @user))
+			)
+			AND NOT EXISTS (
+				SELECT 1 FROM friend_requests r WHERE r.deleted_at IS NULL AND r.status = 'pending'
+				AND ((r.sender_id = @user AND r.receiver_id = c.candidate_id) OR (r.sender_id = c.candidate_id AND r.receiver_id = @user))
+			)
+		ORDER BY c.mutual_count * 2 + c.shared_group_count DESC, c.mutual_count DESC, u.id ASC
+		LIMIT @limit OFFSET @offset`, params).Scan(&suggestions).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Arkadaş önerileri alınamadı"})
+		return
+	}
+
+	if len(suggestions) == 0 {
+		c.JSON(http.StatusOK, gin.H{"suggestions": suggestions, "page": page, "limit": limit})
+		return
+	}
+
+	// 🔥 Her öneri için en fazla 3 ortak arkadaşı tek sorguda getir
+	candidateIDs := make([]uint, len(suggestions))
+	for i, suggestion := range suggestions {
+		candidateIDs[i] = suggestion.ID
+	}
+	params["candidates"] = candidateIDs
+
+	var previews []mutualFriendPreview
+	err = database.DB.Raw(`
+		SELECT candidate_id, id, username, first_name, last_name FROM (
+			SELECT f.friend_id AS candidate_id, u.id, u.username, u.first_name, u.last_name,
+				ROW_NUMBER() OVER (PARTITION BY f.friend_id ORDER BY u.id) AS rn
+			FROM friendships f
+			JOIN friendships mf ON mf.friend_id = f.user_id AND mf.user_id = @user AND mf.deleted_at IS NULL
+			JOIN users u ON u.id = f.user_id AND u.deleted_at IS NULL
+			WHERE f.friend_id IN @candidates AND f.deleted_at IS NULL
+		) ranked
+		WHERE rn <= 3`, params).Scan(&previews).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Arkadaş önerileri alınamadı"})
+		return
+	}
+
+	byCandidate := make(map[uint][]mutualFriendPreview)
+	for _, preview := range previews {
+		byCandidate[preview.CandidateID] = append(byCandidate[preview.CandidateID], preview)
+	}
+	for i := range suggestions {
+		suggestions[i].MutualFriends = byCandidate[suggestions[i].ID]
+		if suggestions[i].MutualFriends == nil {
+			suggestions[i].MutualFriends = []mutualFriendPreview{}
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"suggestions": suggestions, "page": page, "limit": limit})
+}
@@ -0,0 +1,42 @@
+package routes
+
+import (
+	"fmt"
+
+	"arcurachat_api/models"
+
+	"gorm.io/gorm"
+)
+
+// ✅ Sistem mesajı için varsayılan metin
+// İstemciler Event ve Payload alanlarını kullanarak kendi dillerinde gösterebilir.
+func renderSystemMessage(event string, payload models.JSONMap) string {
+	switch event {
+	case models.SystemEventMemberAdded:
+		return fmt.Sprintf("Kullanıcı %v gruba eklendi", payload["user_id"])
+	case models.SystemEventMemberRemoved:
+		return fmt.Sprintf("Kullanıcı %v gruptan çıkarıldı", payload["user_id"])
+	case models.SystemEventGroupRenamed:
+		return fmt.Sprintf("Grup adı \"%v\" olarak değiştirildi", payload["new_name"])
+	case models.SystemEventMessagePinned:
+		return fmt.Sprintf("Kullanıcı %v bir mesajı sabitledi", payload["user_id"])
+	default:
+		return ""
+	}
+}
+
+// 🔥 Konuşmaya sistem mesajı yaz
+func createSystemMessage(tx *gorm.DB, conversationID uint, event string, payload models.JSONMap) error {
+	if conversationID == 0 {
+		return nil
+	}
+
+	message := models.Message{
+		ConversationID: conversationID,
+		Type:           models.MessageTypeSystem,
+		Event:          event,
+		Payload:        payload,
+		Content:        renderSystemMessage(event, payload),
+	}
+	return tx.Create(&message).Error
+}
@@ -0,0 +1,113 @@
+package routes
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"arcurachat_api/database"
+	"arcurachat_api/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var (
+	testDBOnce sync.Once
+	testDB     *gorm.DB
+	testDBErr  error
+)
+
+// ✅ Test veritabanını hazırla
+// TEST_DATABASE_DSN tanımlı değilse veritabanı gerektiren testler atlanır.
+// Her test kendi transaction'ı içinde çalışır ve sonunda geri alınır.
+func setupTestDB(t *testing.T) {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN tanımlı değil")
+	}
+	testDBOnce.Do(func() {
+		testDB, testDBErr = gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+		if testDBErr == nil {
+			database.Migrate(testDB)
+		}
+	})
+	if testDBErr != nil {
+		t.Fatalf("test veritabanına bağlanılamadı: %v", testDBErr)
+	}
+
+	tx := testDB.Begin()
+	previous := database.DB
+	database.DB = tx
+	t.Cleanup(func() {
+		tx.Rollback()
+		database.DB = previous
+	})
+}
+
+// ✅ Test kullanıcısı oluştur
+func createTestUser(t *testing.T, name string) models.User {
+	t.Helper()
+
+	suffix := time.Now().UnixNano()
+	user := models.User{
+		FirstName:   name,
+		LastName:    "Test",
+		Username:    fmt.Sprintf("%s_%d", name, suffix),
+		Email:       fmt.Sprintf("%s_%d@example.com", name, suffix),
+		PhoneNumber: fmt.Sprintf("+90%d", suffix%10000000000),
+		Password:    "x",
+	}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("kullanıcı oluşturulamadı: %v", err)
+	}
+	return user
+}
+
+// ✅ Konuşmaya verilen zamanda mesaj ekle
+func createTestMessage(t *testing.T, conversationID, senderID uint, content string, at time.Time) models.Message {
+	t.Helper()
+
+	message := models.Message{ConversationID: conversationID, SenderID: senderID, Content: content}
+	message.CreatedAt = at
+	if err := database.DB.Create(&message).Error; err != nil {
+		t.Fatalf("mesaj oluşturulamadı: %v", err)
+	}
+	return message
+}
+
+// ✅ Handler'ı verilen kullanıcı adına çalıştır
+func performRequest(t *testing.T, method, route, path string, userID uint, body interface{}, handler gin.HandlerFunc) *httptest.ResponseRecorder {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Handle(method, route, func(c *gin.Context) {
+		c.Set("userID", userID)
+	}, handler)
+
+	var reader *bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
@@ -0,0 +1,241 @@
+package routes
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"arcurachat_api/database"
+	"arcurachat_api/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Alıntı kopyasında saklanan en fazla karakter sayısı
+const quoteSnapshotLength = 200
+
+// ✅ Alıntılanan mesajın gönderim anındaki kopyası
+// Asıl mesaj silinirse istemciye bu kopya gösterilir.
+func quoteSnapshot(message models.Message) models.JSONMap {
+	content := []rune(message.Content)
+	if len(content) > quoteSnapshotLength {
+		content = append(content[:quoteSnapshotLength], '…')
+	}
+	return models.JSONMap{
+		"message_id": message.ID,
+		"sender_id":  message.SenderID,
+		"content":    string(content),
+		"created_at": message.CreatedAt,
+	}
+}
+
+// 🔥 Yanıt ve başlık hedeflerini doğrula
+// Alıntılanan mesaj ve başlık kökü aynı konuşmada olmalı ve kullanıcı tarafından görülebilmelidir.
+func validateReplyTargets(conversationID, userID uint, replyToID, threadRootID *uint) (*models.Message, int, string) {
+	if threadRootID != nil {
+		var root models.Message
+		if err := database.DB.First(&root, *threadRootID).Error; err != nil || root.ConversationID != conversationID || !canViewMessage(root, userID) {
+			return nil, http.StatusNotFound, "Başlık mesajı bulunamadı"
+		}
+		if root.DeletedForEveryoneAt != nil {
+			return nil, http.StatusBadRequest, "Silinmiş mesaja başlık açılamaz"
+		}
+		if root.ThreadRootID != nil || root.Type != models.MessageTypeUser {
+			return nil, http.StatusBadRequest, "Bu mesaja başlık açılamaz"
+		}
+	}
+
+	if replyToID == nil {
+		return nil, 0, ""
+	}
+
+	var replyTo models.Message
+	if err := database.DB.First(&replyTo, *replyToID).Error; err != nil || replyTo.ConversationID != conversationID || !canViewMessage(replyTo, userID) {
+		return nil, http.StatusNotFound, "Yanıtlanan mesaj bulunamadı"
+	}
+	if replyTo.DeletedForEveryoneAt != nil {
+		return nil, http.StatusBadRequest, "Silinmiş mesaj yanıtlanamaz"
+	}
+	return &replyTo, 0, ""
+}
+
+// ✅ Alıntıları güncel içerikle doldur
+// Asıl mesaj silinmiş (herkesten silme dahil) veya artık görülemiyorsa gönderim anındaki kopya "deleted" işaretiyle döner.
+func attachQuotes(messages []models.Message, userID uint) {
+	var ids []uint
+	for _, message := range messages {
+		if message.ReplyToID != nil {
+			ids = append(ids, *message.ReplyToID)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	var originals []models.Message
+	database.DB.Scopes(accessibleMessages(userID)).
+		Where("messages.id IN ? AND messages.deleted_for_everyone_at IS NULL", ids).
+		Find(&originals)
+	live := make(map[uint]models.Message, len(originals))
+	for _, original := range originals {
+		live[original.ID] = original
+	}
+
+	for i := range messages {
+		if messages[i].ReplyToID == nil {
+			continue
+		}
+		if original, ok := live[*messages[i].ReplyToID]; ok {
+			messages[i].Quote = quoteSnapshot(original)
+			continue
+		}
+		if messages[i].Quote == nil {
+			messages[i].Quote = models.JSONMap{"message_id": *messages[i].ReplyToID}
+		}
+		messages[i].Quote["deleted"] = true
+	}
+}
+
+// ✅ Kök mesajlara yanıt sayısı, son yanıt zamanı ve okunmamış yanıt sayısını ekle
+func attachThreadSummaries(messages []models.Message, userID uint) {
+	if len(messages) == 0 {
+		return
+	}
+
+	ids := make([]uint, 0, len(messages))
+	for _, message := range messages {
+		ids = append(ids, message.ID)
+	}
+
+	var summaries []struct {
+		ThreadRootID uint
+		ReplyCount   int64
+		LastReplyAt  time.Time
+		UnreadCount  int64
+	}
+	database.DB.Model(&models.Message{}).
+		Scopes(accessibleMessages(userID)).
+		Select(`messages.thread_root_id, COUNT(*) AS reply_count, MAX(messages.created_at) AS last_reply_at,
+			COUNT(*) FILTER (WHERE messages.sender_id <> ? AND messages.id > COALESCE(trs.last_read_message_id, 0)) AS unread_count`, userID).
+		Joins("LEFT JOIN thread_read_states trs ON trs.root_message_id = messages.thread_root_id AND trs.user_id = ? AND trs.deleted_at IS NULL", userID).
+		Where("messages.thread_root_id IN ?", ids).
+		Group("messages.thread_root_id").
+		Scan(&summaries)
+
+	byRoot := make(map[uint]int, len(messages))
+	for i := range messages {
+		byRoot[messages[i].ID] = i
+	}
+	for _, summary := range summaries {
+		i := byRoot[summary.ThreadRootID]
+		lastReplyAt := summary.LastReplyAt
+		messages[i].ReplyCount = summary.ReplyCount
+		messages[i].LastReplyAt = &lastReplyAt
+		messages[i].ThreadUnreadCount = summary.UnreadCount
+	}
+}
+
+// ✅ Başlıktaki okuma konumunu ilerlet
+func advanceThreadRead(tx *gorm.DB, rootMessageID, userID, messageID uint) error {
+	state := models.ThreadReadState{UserID: userID, RootMessageID: rootMessageID, LastReadMessageID: messageID}
+	return tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "root_message_id"}},
+		DoUpdates: clause.Set{{
+			Column: clause.Column{Name: "last_read_message_id"},
+			Value:  gorm.Expr("GREATEST(thread_read_states.last_read_message_id, EXCLUDED.last_read_message_id)"),
+		}},
+	}).Create(&state).Error
+}
+
+// ✅ URL'deki kök mesajı getir ve erişimi kontrol et
+func threadRootFromParams(c *gin.Context, userID uint) (models.Message, bool) {
+	var root models.Message
+
+	conversationID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz konuşma ID"})
+		return root, false
+	}
+
+	if err := database.DB.First(&root, c.Param("message_id")).Error; err != nil ||
+		root.ConversationID != uint(conversationID) || root.ThreadRootID != nil || !canViewMessage(root, userID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Başlık bulunamadı"})
+		return root, false
+	}
+	return root, true
+}
+
+// 🔥 Başlığı Getir (GET /conversations/:id/threads/:message_id)
+func GetThread(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	root, ok := threadRootFromParams(c, userID.(uint))
+	if !ok {
+		return
+	}
+
+	page, limit := getPagination(c)
+	var replies []models.Message
+	if err := database.DB.Scopes(accessibleMessages(userID.(uint))).
+		Where("thread_root_id = ?", root.ID).
+		Order("id ASC").
+		Offset((page - 1) * limit).Limit(limit).
+		Find(&replies).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Başlık alınamadı"})
+		return
+	}
+
+	roots := []models.Message{root}
+	attachQuotes(roots, userID.(uint))
+	attachThreadSummaries(roots, userID.(uint))
+	attachReactions(roots, userID.(uint))
+	attachAttachments(roots)
+	attachQuotes(replies, userID.(uint))
+	attachReactions(replies, userID.(uint))
+	attachAttachments(replies)
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"root":    roots[0],
+			"replies": replies,
+		},
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// ✅ Başlığı Okundu İşaretle (POST /conversations/:id/threads/:message_id/read)
+func MarkThreadAsRead(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yetkisiz işlem"})
+		return
+	}
+
+	root, ok := threadRootFromParams(c, userID.(uint))
+	if !ok {
+		return
+	}
+
+	var lastReply models.Message
+	if err := database.DB.Scopes(accessibleMessages(userID.(uint))).
+		Where("thread_root_id = ?", root.ID).
+		Order("id DESC").First(&lastReply).Error; err != nil {
+		c.JSON(http.StatusOK, gin.H{"message": "Başlıkta okunmamış yanıt yok"})
+		return
+	}
+
+	if err := advanceThreadRead(database.DB, root.ID, userID.(uint), lastReply.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Başlık okundu olarak işaretlenemedi"})
+		return
+	}
+	markMentionsRead(userID.(uint), root.ConversationID, &root.ID, lastReply.ID)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Başlık okundu olarak işaretlendi"})
+}
@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ✅ Yerel disk depolaması
+// İndirme bağlantıları API'nin /files route'una HMAC ile imzalanmış süreli URL'lerdir.
+type LocalStorage struct {
+	dir     string
+	baseURL string
+	secret  []byte
+}
+
+// ✅ Yeni yerel depolama oluştur
+func NewLocalStorage(dir, baseURL string, secret []byte) *LocalStorage {
+	return &LocalStorage{dir: dir, baseURL: strings.TrimRight(baseURL, "/"), secret: secret}
+}
+
+// Anahtarı depolama dizini dışına çıkamayacak bir dosya yoluna çevir
+func (l *LocalStorage) path(key string) (string, error) {
+	cleaned := filepath.Clean("/" + key)
+	if cleaned == "/" {
+		return "", fmt.Errorf("geçersiz anahtar: %q", key)
+	}
+	return filepath.Join(l.dir, cleaned), nil
+}
+
+func (l *LocalStorage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+
+	// Yarım kalan yazmalar görünmesin diye önce geçici dosyaya yazılır
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (l *LocalStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return file, err
+}
+
+func (l *LocalStorage) Delete(ctx context.Context, key string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (l *LocalStorage) SignedURL(key string, expires time.Duration) (string, error) {
+	expiresAt := strconv.FormatInt(time.Now().Add(expires).Unix(), 10)
+	return fmt.Sprintf("%s/%s?expires=%s&signature=%s", l.baseURL, key, expiresAt, l.sign(key, expiresAt)), nil
+}
+
+// 🔥 İmzalı URL geçerli mi? (süresi dolmamış ve imza doğru)
+func (l *LocalStorage) Verify(key, expiresAt, signature string) bool {
+	unix, err := strconv.ParseInt(expiresAt, 10, 64)
+	if err != nil || time.Now().Unix() > unix {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(l.sign(key, expiresAt)))
+}
+
+func (l *LocalStorage) sign(key, expiresAt string) string {
+	mac := hmac.New(sha256.New, l.secret)
+	mac.Write([]byte(key + "\n" + expiresAt))
+	return hex.EncodeToString(mac.Sum(nil))
+}
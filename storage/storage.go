@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"arcurachat_api/utils"
+)
+
+// ✅ Dosya bulunamadığında dönen hata
+var ErrNotFound = errors.New("dosya bulunamadı")
+
+// 🔥 Dosya depolama arayüzü
+// Yerel disk ve S3 uyumlu (AWS S3, MinIO) arka uçlar bu arayüzü uygular.
+type Storage interface {
+	// Put dosyayı verilen anahtarla kaydeder
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Open dosyayı okumak için açar
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete dosyayı siler; dosya yoksa hata dönmez
+	Delete(ctx context.Context, key string) error
+	// SignedURL dosya için süreli indirme bağlantısı üretir
+	SignedURL(key string, expires time.Duration) (string, error)
+}
+
+// 🔥 Varsayılan depolama (STORAGE_BACKEND=local|s3), Init ile hazırlanır
+var Default Storage
+
+// ✅ Varsayılan depolamayı ortam değişkenlerinden hazırla
+// Sunucu açılışında çağrılır; yapılandırma eksikse sunucu başlatılmaz.
+func Init() {
+	Default = newFromEnv()
+}
+
+func newFromEnv() Storage {
+	switch backend := utils.GetEnv("STORAGE_BACKEND", "local"); backend {
+	case "s3":
+		return NewS3Storage(S3Config{
+			Endpoint:  utils.GetEnv("S3_ENDPOINT", "localhost:9000"),
+			Region:    utils.GetEnv("S3_REGION", "us-east-1"),
+			Bucket:    utils.GetEnv("S3_BUCKET", "arcurachat"),
+			AccessKey: utils.GetEnv("S3_ACCESS_KEY", ""),
+			SecretKey: utils.GetEnv("S3_SECRET_KEY", ""),
+			UseSSL:    utils.GetEnvBool("S3_USE_SSL", false),
+		})
+	case "local":
+		return NewLocalStorage(
+			utils.GetEnv("LOCAL_STORAGE_DIR", "./uploads"),
+			utils.GetEnv("LOCAL_STORAGE_URL", "http://localhost:8080/files"),
+			fileURLSecret(),
+		)
+	default:
+		log.Fatalf("Hata: Bilinmeyen STORAGE_BACKEND %q", backend)
+		return nil
+	}
+}
+
+// İmzalı dosya bağlantıları için anahtarın en kısa uzunluğu (bayt)
+const minFileURLSecretLength = 32
+
+// 🔥 İmzalı dosya bağlantılarının anahtarı
+// JWT anahtarından ayrı tutulur; tanımlı değilse veya kısaysa sunucu başlatılmaz.
+func fileURLSecret() []byte {
+	secret := utils.GetEnv("FILE_URL_SECRET", "")
+	if len(secret) < minFileURLSecretLength {
+		log.Fatalf("Hata: FILE_URL_SECRET en az %d karakter olmalıdır", minFileURLSecretLength)
+	}
+	return []byte(secret)
+}
+
+// ✅ Tahmin edilemeyen depolama anahtarı üret (örn. attachments/42/9f86d0...)
+func NewKey(prefix string, ownerID uint) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/%d/%s", prefix, ownerID, hex.EncodeToString(buf)), nil
+}
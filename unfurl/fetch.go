@@ -0,0 +1,181 @@
+package unfurl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	fetchTimeout = 5 * time.Second
+	maxRedirects = 3
+	maxBodySize  = 512 << 10
+	userAgent    = "ArcuraChatBot/1.0 (+link preview)"
+)
+
+var (
+	// ✅ Özel/yerel ağ adresine erişim engellendiğinde dönen hata
+	ErrBlockedAddress = errors.New("bu adrese erişim engellendi")
+	// ✅ Yanıt HTML değilse dönen hata
+	ErrNotHTML = errors.New("sayfa HTML değil")
+)
+
+// 🔥 Sunucu tarafı isteklerde (SSRF) engellenen ağlar
+// Loopback, özel ağlar, link-local (bulut metadata servisleri dahil), CGNAT ve çoklu yayın adresleri.
+// IPv4 adresini içinde taşıyan IPv6 aralıkları (IPv4 uyumlu, NAT64, Teredo, 6to4) da engellenir.
+var blockedNetworks = mustParseCIDRs(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.0.0.0/24",
+	"192.0.2.0/24",
+	"192.168.0.0/16",
+	"198.18.0.0/15",
+	"198.51.100.0/24",
+	"203.0.113.0/24",
+	"224.0.0.0/4",
+	"240.0.0.0/4",
+	"::/96",
+	"::1/128",
+	"64:ff9b::/96",
+	"64:ff9b:1::/48",
+	"100::/64",
+	"2001::/32",
+	"2001:db8::/32",
+	"2002::/16",
+	"fc00::/7",
+	"fe80::/10",
+	"ff00::/8",
+)
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+// ✅ IP adresi herkese açık bir adres mi?
+func isPublicIP(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	for _, network := range blockedNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// Bağlantı, DNS çözümlemesinden sonra IP üzerinden kontrol edilerek kurulur;
+// böylece DNS rebinding ile özel adreslere yönlendirme yapılamaz.
+func safeDialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	if port != "80" && port != "443" {
+		return nil, ErrBlockedAddress
+	}
+
+	ips, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	// Adreslerden biri bile özel ağdaysa istek yapılmaz
+	for _, ip := range ips {
+		if !isPublicIP(ip.IP) {
+			return nil, ErrBlockedAddress
+		}
+	}
+
+	dialer := &net.Dialer{Timeout: fetchTimeout}
+	var lastErr error = ErrBlockedAddress
+	for _, ip := range ips {
+		conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(ip.IP.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+var client = &http.Client{
+	Timeout: fetchTimeout,
+	Transport: &http.Transport{
+		Proxy:                 nil, // Ortam proxy ayarları kullanılmaz, bağlantı her zaman kontrol edilir
+		DialContext:           safeDialContext,
+		TLSHandshakeTimeout:   fetchTimeout,
+		ResponseHeaderTimeout: fetchTimeout,
+		MaxIdleConns:          16,
+		IdleConnTimeout:       30 * time.Second,
+	},
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("en fazla %d yönlendirme izlenir", maxRedirects)
+		}
+		if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+			return ErrBlockedAddress
+		}
+		return nil
+	},
+}
+
+// ✅ URL önizleme için getirilebilir mi? (sadece http/https, kullanıcı bilgisi içermeyen)
+func ValidURL(raw string) (*url.URL, bool) {
+	parsed, err := url.Parse(raw)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" || parsed.User != nil {
+		return nil, false
+	}
+	return parsed, true
+}
+
+// 🔥 Sayfayı güvenli şekilde getir
+// Sadece HTML yanıtlar kabul edilir ve gövdenin ilk maxBodySize baytı okunur.
+func fetch(ctx context.Context, target *url.URL) ([]byte, *url.URL, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, nil, fmt.Errorf("sayfa %d döndü", resp.StatusCode)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return nil, nil, ErrNotHTML
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return nil, nil, err
+	}
+	// Yönlendirmelerden sonraki son adres, göreli görsel adreslerini çözmek için döner
+	return body, resp.Request.URL, nil
+}
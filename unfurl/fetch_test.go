@@ -0,0 +1,131 @@
+package unfurl
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestIsPublicIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"8.8.8.8", true},
+		{"1.1.1.1", true},
+		{"2606:4700:4700::1111", true},
+		{"127.0.0.1", false},
+		{"10.0.0.1", false},
+		{"172.16.5.4", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"224.0.0.1", false},
+		{"255.255.255.255", false},
+		{"::", false},
+		{"::1", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"ff02::1", false},
+		// IPv4 eşlemeli IPv6 adresleri IPv4 karşılığına göre değerlendirilir
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:10.0.0.1", false},
+		{"::ffff:169.254.169.254", false},
+		{"::ffff:8.8.8.8", true},
+		// IPv4 uyumlu (eski) adresler
+		{"::127.0.0.1", false},
+		{"::a00:1", false},
+		// NAT64 (RFC 6052 ve RFC 8215)
+		{"64:ff9b::7f00:1", false},
+		{"64:ff9b::a9fe:a9fe", false},
+		{"64:ff9b:1::a00:1", false},
+		// Teredo ve 6to4
+		{"2001:0:4136:e378:8000:63bf:3fff:fdd2", false},
+		{"2002:a00:1::1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			ip := net.ParseIP(tt.ip)
+			if ip == nil {
+				t.Fatalf("invalid test IP %q", tt.ip)
+			}
+			if got := isPublicIP(ip); got != tt.want {
+				t.Fatalf("isPublicIP(%s) = %v, want %v", tt.ip, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSafeDialContextBlocksPrivateAddresses(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+	}{
+		{"loopback", "127.0.0.1:80"},
+		{"localhost", "localhost:443"},
+		{"metadata", "169.254.169.254:80"},
+		{"ipv4 mapped", "[::ffff:127.0.0.1]:80"},
+		{"ipv4 mapped private", "[::ffff:192.168.0.1]:443"},
+		{"nat64", "[64:ff9b::7f00:1]:80"},
+		{"ipv6 loopback", "[::1]:443"},
+		{"disallowed port", "8.8.8.8:22"},
+		{"disallowed port on private", "10.0.0.1:8080"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, err := safeDialContext(context.Background(), "tcp", tt.address)
+			if conn != nil {
+				conn.Close()
+			}
+			if !errors.Is(err, ErrBlockedAddress) {
+				t.Fatalf("safeDialContext(%s) error = %v, want ErrBlockedAddress", tt.address, err)
+			}
+		})
+	}
+}
+
+func TestCheckRedirect(t *testing.T) {
+	request := func(raw string) *http.Request {
+		target, err := url.Parse(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &http.Request{URL: target}
+	}
+	via := func(n int) []*http.Request {
+		requests := make([]*http.Request, n)
+		for i := range requests {
+			requests[i] = request("https://example.com/")
+		}
+		return requests
+	}
+
+	tests := []struct {
+		name    string
+		target  string
+		via     int
+		wantErr bool
+	}{
+		{"first redirect", "https://example.com/a", 1, false},
+		{"last allowed redirect", "http://example.com/b", maxRedirects - 1, false},
+		{"too many redirects", "https://example.com/c", maxRedirects, true},
+		{"far too many redirects", "https://example.com/d", maxRedirects + 5, true},
+		{"non http scheme", "file:///etc/passwd", 1, true},
+		{"gopher scheme", "gopher://127.0.0.1:70/", 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := client.CheckRedirect(request(tt.target), via(tt.via))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckRedirect() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
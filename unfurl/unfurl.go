@@ -0,0 +1,153 @@
+package unfurl
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	maxTitleLength       = 300
+	maxDescriptionLength = 1000
+)
+
+// ✅ Bağlantı önizlemesi
+type Preview struct {
+	URL         string `json:"url"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	ImageURL    string `json:"image_url,omitempty"`
+	SiteName    string `json:"site_name,omitempty"`
+}
+
+var urlPattern = regexp.MustCompile(`https?://[^\s<>"'` + "`" + `]+`)
+
+// ✅ Metindeki ilk geçerli bağlantıyı bul (sondaki noktalama işaretleri atılır)
+func FirstURL(text string) string {
+	for _, match := range urlPattern.FindAllString(text, -1) {
+		match = strings.TrimRight(match, ".,;:!?)]}")
+		if _, ok := ValidURL(match); ok {
+			return match
+		}
+	}
+	return ""
+}
+
+// 🔥 Bağlantının Open Graph önizlemesini getir
+// og:* etiketleri yoksa <title> ve description meta etiketine düşülür.
+func Fetch(ctx context.Context, raw string) (*Preview, error) {
+	target, ok := ValidURL(raw)
+	if !ok {
+		return nil, ErrBlockedAddress
+	}
+
+	body, finalURL, err := fetch(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := parse(body, finalURL)
+	preview.URL = raw
+	return preview, nil
+}
+
+func parse(body []byte, base *url.URL) *Preview {
+	preview := &Preview{}
+	var fallbackTitle, fallbackDescription string
+
+	tokenizer := html.NewTokenizer(bytes.NewReader(body))
+	inTitle := false
+scan:
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			break scan
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			switch token.Data {
+			case "title":
+				inTitle = fallbackTitle == ""
+			case "meta":
+				key, content := metaAttributes(token)
+				switch key {
+				case "og:title":
+					preview.Title = content
+				case "og:description":
+					preview.Description = content
+				case "og:site_name":
+					preview.SiteName = truncate(strings.TrimSpace(content), maxTitleLength)
+				case "og:image", "og:image:url", "og:image:secure_url":
+					if preview.ImageURL == "" {
+						preview.ImageURL = resolveImage(base, content)
+					}
+				case "description":
+					fallbackDescription = content
+				}
+			case "body":
+				// Meta etiketleri head içinde olur; gövdenin tamamını taramaya gerek yok
+				break scan
+			}
+		case html.TextToken:
+			if inTitle {
+				fallbackTitle = string(tokenizer.Text())
+			}
+		case html.EndTagToken:
+			inTitle = false
+		}
+	}
+
+	if preview.Title == "" {
+		preview.Title = fallbackTitle
+	}
+	if preview.Description == "" {
+		preview.Description = fallbackDescription
+	}
+	preview.Title = truncate(strings.TrimSpace(preview.Title), maxTitleLength)
+	preview.Description = truncate(strings.TrimSpace(preview.Description), maxDescriptionLength)
+	return preview
+}
+
+// ✅ Önizlemede gösterilecek hiçbir bilgi yok mu?
+func (p *Preview) Empty() bool {
+	return p.Title == "" && p.Description == "" && p.ImageURL == ""
+}
+
+func metaAttributes(token html.Token) (string, string) {
+	var key, content string
+	for _, attr := range token.Attr {
+		switch strings.ToLower(attr.Key) {
+		case "property", "name":
+			if key == "" {
+				key = strings.ToLower(strings.TrimSpace(attr.Val))
+			}
+		case "content":
+			content = attr.Val
+		}
+	}
+	return key, content
+}
+
+// Göreli görsel adresini çöz; sadece http/https adresler kabul edilir
+func resolveImage(base *url.URL, raw string) string {
+	ref, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return ""
+	}
+	resolved := base.ResolveReference(ref)
+	if _, ok := ValidURL(resolved.String()); !ok {
+		return ""
+	}
+	return resolved.String()
+}
+
+func truncate(value string, length int) string {
+	if utf8.RuneCountInString(value) <= length {
+		return value
+	}
+	return string([]rune(value)[:length]) + "…"
+}
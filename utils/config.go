@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// ✅ Tam sayı çevresel değişken okuma fonksiyonu
+func GetEnvInt(key string, fallback int) int {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Hata: %s tam sayı değil, varsayılan kullanılıyor - %v", key, err)
+		return fallback
+	}
+	return parsed
+}
+
+// ✅ Süre çevresel değişken okuma fonksiyonu (örn. "72h", "30m")
+func GetEnvDuration(key string, fallback time.Duration) time.Duration {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Hata: %s geçerli bir süre değil, varsayılan kullanılıyor - %v", key, err)
+		return fallback
+	}
+	return parsed
+}
+
+// ✅ Metin çevresel değişken okuma fonksiyonu
+func GetEnv(key, fallback string) string {
+	return getEnv(key, fallback)
+}
+
+// ✅ Mantıksal çevresel değişken okuma fonksiyonu (örn. "true", "0")
+func GetEnvBool(key string, fallback bool) bool {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Hata: %s true/false değil, varsayılan kullanılıyor - %v", key, err)
+		return fallback
+	}
+	return parsed
+}
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// ✅ Ülke kodu olmadan girilen numaralar için varsayılan ülke kodu
+var defaultCountryCode = getEnv("DEFAULT_PHONE_COUNTRY_CODE", "90")
+
+// ✅ Telefon numarasını E.164 formatına çevir (örn. "0532 123 45 67" -> "+905321234567")
+func NormalizePhoneNumber(phone string) (string, error) {
+	phone = strings.TrimSpace(phone)
+
+	var digits strings.Builder
+	for i, r := range phone {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == '+' && i == 0:
+		case r == ' ' || r == '-' || r == '(' || r == ')' || r == '.':
+		default:
+			return "", errors.New("telefon numarası geçersiz karakter içeriyor")
+		}
+	}
+
+	number := digits.String()
+	switch {
+	case strings.HasPrefix(phone, "+"):
+	case strings.HasPrefix(number, "00"):
+		number = number[2:]
+	case strings.HasPrefix(number, "0"):
+		number = defaultCountryCode + number[1:]
+	default:
+		number = defaultCountryCode + number
+	}
+
+	// E.164 en fazla 15 hane olabilir
+	if len(number) < 8 || len(number) > 15 {
+		return "", errors.New("telefon numarası uzunluğu geçersiz")
+	}
+
+	return "+" + number, nil
+}
+
+// ✅ E.164 numaranın SHA-256 özeti (istemci de aynı şekilde hesaplar)
+func HashPhoneNumber(e164 string) string {
+	sum := sha256.Sum256([]byte(e164))
+	return hex.EncodeToString(sum[:])
+}
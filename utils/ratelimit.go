@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// ✅ Bellek içi sabit pencereli hız sınırlayıcı
+// Tek API örneği için anahtar başına (örn. kullanıcı ID) istek sayısını sınırlar.
+type RateLimiter struct {
+	mu      sync.Mutex
+	limit   int
+	window  time.Duration
+	windows map[string]*rateWindow
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// ✅ Yeni hız sınırlayıcı oluştur
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{
+		limit:   limit,
+		window:  window,
+		windows: make(map[string]*rateWindow),
+	}
+}
+
+// ✅ İsteğe izin verilip verilmediğini döndür, verilmediyse ne kadar beklenmesi gerektiğini de
+func (r *RateLimiter) Allow(key string) (bool, time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	w, ok := r.windows[key]
+	if !ok || now.Sub(w.start) >= r.window {
+		if len(r.windows) > 1024 {
+			r.cleanup(now)
+		}
+		r.windows[key] = &rateWindow{start: now, count: 1}
+		return true, 0
+	}
+
+	if w.count >= r.limit {
+		return false, r.window - now.Sub(w.start)
+	}
+
+	w.count++
+	return true, 0
+}
+
+// Süresi dolmuş pencereleri temizle (kilit altında çağrılır)
+func (r *RateLimiter) cleanup(now time.Time) {
+	for key, w := range r.windows {
+		if now.Sub(w.start) >= r.window {
+			delete(r.windows, key)
+		}
+	}
+}